@@ -19,12 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SentryService_StartJob_FullMethodName      = "/sentry.SentryService/StartJob"
-	SentryService_StopJob_FullMethodName       = "/sentry.SentryService/StopJob"
-	SentryService_KillJob_FullMethodName       = "/sentry.SentryService/KillJob"
-	SentryService_GetJobStatus_FullMethodName  = "/sentry.SentryService/GetJobStatus"
-	SentryService_StreamJobLogs_FullMethodName = "/sentry.SentryService/StreamJobLogs"
-	SentryService_ListJobs_FullMethodName      = "/sentry.SentryService/ListJobs"
+	SentryService_StartJob_FullMethodName          = "/sentry.SentryService/StartJob"
+	SentryService_StopJob_FullMethodName           = "/sentry.SentryService/StopJob"
+	SentryService_KillJob_FullMethodName           = "/sentry.SentryService/KillJob"
+	SentryService_GetJobStatus_FullMethodName      = "/sentry.SentryService/GetJobStatus"
+	SentryService_WaitJob_FullMethodName           = "/sentry.SentryService/WaitJob"
+	SentryService_StreamJobLogs_FullMethodName     = "/sentry.SentryService/StreamJobLogs"
+	SentryService_ListJobs_FullMethodName          = "/sentry.SentryService/ListJobs"
+	SentryService_RemoveJob_FullMethodName         = "/sentry.SentryService/RemoveJob"
+	SentryService_PruneJobs_FullMethodName         = "/sentry.SentryService/PruneJobs"
+	SentryService_GetCgroupGCReport_FullMethodName = "/sentry.SentryService/GetCgroupGCReport"
+	SentryService_UpdateJobLimits_FullMethodName   = "/sentry.SentryService/UpdateJobLimits"
+	SentryService_GetJobStats_FullMethodName       = "/sentry.SentryService/GetJobStats"
 )
 
 // SentryServiceClient is the client API for SentryService service.
@@ -35,8 +41,14 @@ type SentryServiceClient interface {
 	StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error)
 	KillJob(ctx context.Context, in *KillJobRequest, opts ...grpc.CallOption) (*KillJobResponse, error)
 	GetJobStatus(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (*JobStatusResponse, error)
+	WaitJob(ctx context.Context, in *WaitJobRequest, opts ...grpc.CallOption) (*JobStatusResponse, error)
 	StreamJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobOutput], error)
 	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	RemoveJob(ctx context.Context, in *RemoveJobRequest, opts ...grpc.CallOption) (*RemoveJobResponse, error)
+	PruneJobs(ctx context.Context, in *PruneJobsRequest, opts ...grpc.CallOption) (*PruneJobsResponse, error)
+	GetCgroupGCReport(ctx context.Context, in *CgroupGCReportRequest, opts ...grpc.CallOption) (*CgroupGCReportResponse, error)
+	UpdateJobLimits(ctx context.Context, in *UpdateJobLimitsRequest, opts ...grpc.CallOption) (*UpdateJobLimitsResponse, error)
+	GetJobStats(ctx context.Context, in *JobStatsRequest, opts ...grpc.CallOption) (*JobStatsResponse, error)
 }
 
 type sentryServiceClient struct {
@@ -87,6 +99,16 @@ func (c *sentryServiceClient) GetJobStatus(ctx context.Context, in *JobStatusReq
 	return out, nil
 }
 
+func (c *sentryServiceClient) WaitJob(ctx context.Context, in *WaitJobRequest, opts ...grpc.CallOption) (*JobStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatusResponse)
+	err := c.cc.Invoke(ctx, SentryService_WaitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *sentryServiceClient) StreamJobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobOutput], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &SentryService_ServiceDesc.Streams[0], SentryService_StreamJobLogs_FullMethodName, cOpts...)
@@ -116,6 +138,56 @@ func (c *sentryServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest,
 	return out, nil
 }
 
+func (c *sentryServiceClient) RemoveJob(ctx context.Context, in *RemoveJobRequest, opts ...grpc.CallOption) (*RemoveJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveJobResponse)
+	err := c.cc.Invoke(ctx, SentryService_RemoveJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentryServiceClient) PruneJobs(ctx context.Context, in *PruneJobsRequest, opts ...grpc.CallOption) (*PruneJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PruneJobsResponse)
+	err := c.cc.Invoke(ctx, SentryService_PruneJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentryServiceClient) GetCgroupGCReport(ctx context.Context, in *CgroupGCReportRequest, opts ...grpc.CallOption) (*CgroupGCReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CgroupGCReportResponse)
+	err := c.cc.Invoke(ctx, SentryService_GetCgroupGCReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentryServiceClient) UpdateJobLimits(ctx context.Context, in *UpdateJobLimitsRequest, opts ...grpc.CallOption) (*UpdateJobLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateJobLimitsResponse)
+	err := c.cc.Invoke(ctx, SentryService_UpdateJobLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sentryServiceClient) GetJobStats(ctx context.Context, in *JobStatsRequest, opts ...grpc.CallOption) (*JobStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatsResponse)
+	err := c.cc.Invoke(ctx, SentryService_GetJobStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SentryServiceServer is the server API for SentryService service.
 // All implementations must embed UnimplementedSentryServiceServer
 // for forward compatibility.
@@ -124,8 +196,14 @@ type SentryServiceServer interface {
 	StopJob(context.Context, *StopJobRequest) (*StopJobResponse, error)
 	KillJob(context.Context, *KillJobRequest) (*KillJobResponse, error)
 	GetJobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error)
+	WaitJob(context.Context, *WaitJobRequest) (*JobStatusResponse, error)
 	StreamJobLogs(*JobLogsRequest, grpc.ServerStreamingServer[JobOutput]) error
 	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	RemoveJob(context.Context, *RemoveJobRequest) (*RemoveJobResponse, error)
+	PruneJobs(context.Context, *PruneJobsRequest) (*PruneJobsResponse, error)
+	GetCgroupGCReport(context.Context, *CgroupGCReportRequest) (*CgroupGCReportResponse, error)
+	UpdateJobLimits(context.Context, *UpdateJobLimitsRequest) (*UpdateJobLimitsResponse, error)
+	GetJobStats(context.Context, *JobStatsRequest) (*JobStatsResponse, error)
 	mustEmbedUnimplementedSentryServiceServer()
 }
 
@@ -148,12 +226,30 @@ func (UnimplementedSentryServiceServer) KillJob(context.Context, *KillJobRequest
 func (UnimplementedSentryServiceServer) GetJobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
 }
+func (UnimplementedSentryServiceServer) WaitJob(context.Context, *WaitJobRequest) (*JobStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitJob not implemented")
+}
 func (UnimplementedSentryServiceServer) StreamJobLogs(*JobLogsRequest, grpc.ServerStreamingServer[JobOutput]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamJobLogs not implemented")
 }
 func (UnimplementedSentryServiceServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
 }
+func (UnimplementedSentryServiceServer) RemoveJob(context.Context, *RemoveJobRequest) (*RemoveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveJob not implemented")
+}
+func (UnimplementedSentryServiceServer) PruneJobs(context.Context, *PruneJobsRequest) (*PruneJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneJobs not implemented")
+}
+func (UnimplementedSentryServiceServer) GetCgroupGCReport(context.Context, *CgroupGCReportRequest) (*CgroupGCReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCgroupGCReport not implemented")
+}
+func (UnimplementedSentryServiceServer) UpdateJobLimits(context.Context, *UpdateJobLimitsRequest) (*UpdateJobLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateJobLimits not implemented")
+}
+func (UnimplementedSentryServiceServer) GetJobStats(context.Context, *JobStatsRequest) (*JobStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStats not implemented")
+}
 func (UnimplementedSentryServiceServer) mustEmbedUnimplementedSentryServiceServer() {}
 func (UnimplementedSentryServiceServer) testEmbeddedByValue()                       {}
 
@@ -247,6 +343,24 @@ func _SentryService_GetJobStatus_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SentryService_WaitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentryServiceServer).WaitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SentryService_WaitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentryServiceServer).WaitJob(ctx, req.(*WaitJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _SentryService_StreamJobLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(JobLogsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -276,6 +390,96 @@ func _SentryService_ListJobs_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SentryService_RemoveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentryServiceServer).RemoveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SentryService_RemoveJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentryServiceServer).RemoveJob(ctx, req.(*RemoveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentryService_PruneJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentryServiceServer).PruneJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SentryService_PruneJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentryServiceServer).PruneJobs(ctx, req.(*PruneJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentryService_GetCgroupGCReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CgroupGCReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentryServiceServer).GetCgroupGCReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SentryService_GetCgroupGCReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentryServiceServer).GetCgroupGCReport(ctx, req.(*CgroupGCReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentryService_UpdateJobLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateJobLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentryServiceServer).UpdateJobLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SentryService_UpdateJobLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentryServiceServer).UpdateJobLimits(ctx, req.(*UpdateJobLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SentryService_GetJobStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SentryServiceServer).GetJobStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SentryService_GetJobStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SentryServiceServer).GetJobStats(ctx, req.(*JobStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SentryService_ServiceDesc is the grpc.ServiceDesc for SentryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -299,10 +503,34 @@ var SentryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetJobStatus",
 			Handler:    _SentryService_GetJobStatus_Handler,
 		},
+		{
+			MethodName: "WaitJob",
+			Handler:    _SentryService_WaitJob_Handler,
+		},
 		{
 			MethodName: "ListJobs",
 			Handler:    _SentryService_ListJobs_Handler,
 		},
+		{
+			MethodName: "RemoveJob",
+			Handler:    _SentryService_RemoveJob_Handler,
+		},
+		{
+			MethodName: "PruneJobs",
+			Handler:    _SentryService_PruneJobs_Handler,
+		},
+		{
+			MethodName: "GetCgroupGCReport",
+			Handler:    _SentryService_GetCgroupGCReport_Handler,
+		},
+		{
+			MethodName: "UpdateJobLimits",
+			Handler:    _SentryService_UpdateJobLimits_Handler,
+		},
+		{
+			MethodName: "GetJobStats",
+			Handler:    _SentryService_GetJobStats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
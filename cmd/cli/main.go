@@ -11,24 +11,201 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	pb "github.com/arazmj/sentry-run/api/proto"
+	"github.com/arazmj/sentry-run/pkg/limits"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// formatState renders a job state the way the server logs it, e.g. "oom-killed".
+func formatState(state pb.JobState) string {
+	name := strings.TrimPrefix(state.String(), "JOB_STATE_")
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// parseState is the inverse of formatState.
+func parseState(name string) (pb.JobState, bool) {
+	for value := range pb.JobState_name {
+		if state := pb.JobState(value); formatState(state) == name {
+			return state, true
+		}
+	}
+	return pb.JobState_JOB_STATE_UNSPECIFIED, false
+}
+
+// protoLimits converts parsed limits to the typed API message.
+func protoLimits(l limits.Limits) *pb.ResourceLimits {
+	if l.IsZero() {
+		return nil
+	}
+	p := &pb.ResourceLimits{
+		MemoryMaxBytes: l.MemoryMax,
+		CpuQuotaUs:     l.CPUQuota,
+		CpuPeriodUs:    l.CPUPeriod,
+		PidsMax:        l.PidsMax,
+	}
+	for _, io := range l.IO {
+		p.Io = append(p.Io, &pb.DeviceIOLimit{Device: io.Device, Rbps: io.ReadBps, Wbps: io.WriteBps, Riops: io.ReadIOPS, Wiops: io.WriteIOPS})
+	}
+	return p
+}
+
+// stringList is a flag that can be given several times.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// limitFlags are the resource limit flags of start and update.
+type limitFlags struct {
+	memory, cpu               *string
+	readBps, writeBps         *string
+	readIOPS, writeIOPS, pids *string
+	devices                   stringList
+}
+
+func addLimitFlags(fs *flag.FlagSet) *limitFlags {
+	f := &limitFlags{
+		memory:    fs.String("memory-limit", "", "Memory limit (e.g., '512Mi', '1.5G')"),
+		cpu:       fs.String("cpu-limit", "", "CPU limit in cores (e.g., '0.5', '250m')"),
+		writeBps:  fs.String("wbps-limit", "", "Write bandwidth limit (e.g., '10MB/s')"),
+		readBps:   fs.String("rbps-limit", "", "Read bandwidth limit (e.g., '10MB/s')"),
+		readIOPS:  fs.String("riops-limit", "", "Read IO operations per second limit (e.g., '1000')"),
+		writeIOPS: fs.String("wiops-limit", "", "Write IO operations per second limit (e.g., '1000')"),
+		pids:      fs.String("pids-limit", "", "Maximum number of processes (e.g., '256', 'max')"),
+	}
+	fs.Var(&f.devices, "io-device", "Block device the IO limits apply to, by path, mount point or major:minor (repeatable; default: the root filesystem's disk)")
+	return f
+}
+
+// parse returns the limits given on the command line.
+func (f *limitFlags) parse() (limits.Limits, error) {
+	jobLimits, err := limits.Parse(*f.memory, *f.cpu, "", "")
+	if err != nil {
+		return limits.Limits{}, err
+	}
+	if jobLimits.IO, err = limits.ParseIO(f.devices, *f.readBps, *f.writeBps, *f.readIOPS, *f.writeIOPS); err != nil {
+		return limits.Limits{}, err
+	}
+	if jobLimits.PidsMax, err = limits.ParsePids(*f.pids); err != nil {
+		return limits.Limits{}, fmt.Errorf("invalid PID limit: %v", err)
+	}
+	return jobLimits, nil
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 GiB".
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// printStats prints a job's resource usage. With the previous sample, CPU
+// usage is also shown as a share of one core since then.
+func printStats(resp, prev *pb.JobStatsResponse) {
+	cpu := resp.Cpu
+	fmt.Printf("Collected:  %s\n", resp.CollectedAt.AsTime().Local().Format(time.RFC3339))
+	usage := time.Duration(cpu.UsageUsec) * time.Microsecond
+	fmt.Printf("CPU:        %s (user %s, system %s)", usage, time.Duration(cpu.UserUsec)*time.Microsecond, time.Duration(cpu.SystemUsec)*time.Microsecond)
+	if prev != nil {
+		elapsed := resp.CollectedAt.AsTime().Sub(prev.CollectedAt.AsTime())
+		if elapsed > 0 {
+			used := time.Duration(cpu.UsageUsec-prev.Cpu.UsageUsec) * time.Microsecond
+			fmt.Printf(", %.1f%% of a core", 100*used.Seconds()/elapsed.Seconds())
+		}
+	}
+	fmt.Println()
+	if cpu.NrPeriods > 0 {
+		fmt.Printf("Throttled:  %d of %d periods, %s\n", cpu.NrThrottled, cpu.NrPeriods, time.Duration(cpu.ThrottledUsec)*time.Microsecond)
+	}
+
+	mem := resp.Memory
+	fmt.Printf("Memory:     %s (anon %s, file %s)", formatBytes(mem.CurrentBytes), formatBytes(mem.AnonBytes), formatBytes(mem.FileBytes))
+	if mem.PeakBytes > 0 {
+		fmt.Printf(", peak %s", formatBytes(mem.PeakBytes))
+	}
+	fmt.Println()
+	fmt.Printf("Mem events: high %d, max %d, oom %d, oom_kill %d\n", mem.EventsHigh, mem.EventsMax, mem.EventsOom, mem.EventsOomKill)
+	fmt.Printf("Processes:  %d\n", resp.PidsCurrent)
+	for _, io := range resp.Io {
+		fmt.Printf("IO %-8s read %s (%d ops), write %s (%d ops)\n", io.Device+":", formatBytes(io.ReadBytes), io.ReadIos, formatBytes(io.WriteBytes), io.WriteIos)
+	}
+	printPressure(resp.Pressure)
+}
+
+// printPressure prints the share of time a job was stalled on each
+// resource, averaged over 10s, 60s and 300s.
+func printPressure(pressure *pb.JobPressure) {
+	if pressure == nil {
+		return
+	}
+	for _, r := range []struct {
+		name     string
+		pressure *pb.Pressure
+	}{{"CPU", pressure.Cpu}, {"Memory", pressure.Memory}, {"IO", pressure.Io}} {
+		some, full := r.pressure.GetSome(), r.pressure.GetFull()
+		fmt.Printf("%-11s some %.2f%% %.2f%% %.2f%%, full %.2f%% %.2f%% %.2f%%\n", "PSI "+r.name+":",
+			some.GetAvg10(), some.GetAvg60(), some.GetAvg300(), full.GetAvg10(), full.GetAvg60(), full.GetAvg300())
+	}
+}
+
+const (
+	// exitCodeTimeout matches timeout(1) when wait gives up.
+	exitCodeTimeout = 124
+	// exitCodeNotStarted matches the shell's code for a command that could not be executed.
+	exitCodeNotStarted = 127
+)
+
+// waitExitCode maps a finished job to a shell-style exit code: the job's own
+// exit code, or 128+signal when it was terminated by a signal.
+func waitExitCode(resp *pb.JobStatusResponse) int {
+	switch {
+	case resp.State == pb.JobState_JOB_STATE_FAILED_TO_START:
+		return exitCodeNotStarted
+	case resp.Signal != 0:
+		return 128 + int(resp.Signal)
+	case resp.ExitCode >= 0:
+		return int(resp.ExitCode)
+	default:
+		return 1
+	}
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: cli <command> [options]")
 		fmt.Println("Commands:")
 		fmt.Println("  start   Start a new job")
-		fmt.Println("  stop    Stop a running job")
+		fmt.Println("  stop    Stop a running job (SIGTERM, then SIGKILL after a grace period)")
 		fmt.Println("  status  Get job status")
+		fmt.Println("  wait    Wait for a job to finish and exit with its exit code")
 		fmt.Println("  logs    Get job logs")
 		fmt.Println("  list    List all jobs")
 		fmt.Println("  kill    Kill a job (SIGKILL)")
+		fmt.Println("  update  Change the resource limits of a running job")
+		fmt.Println("  stats   Show the resource usage of a running job")
+		fmt.Println("  rm      Remove a finished job and its logs")
+		fmt.Println("  prune   Remove finished jobs by age or state")
+		fmt.Println("  gc      Show or run the orphaned cgroup collector")
 		os.Exit(1)
 	}
 
@@ -38,25 +215,49 @@ func main() {
 	// Create separate FlagSets for each command
 	startFlags := flag.NewFlagSet("start", flag.ExitOnError)
 	startCmd := startFlags.String("cmd", "", "Command to execute")
-	startMemLimit := startFlags.String("memory-limit", "", "Memory limit (e.g., '100M', '1G')")
-	startCPULimit := startFlags.String("cpu-limit", "", "CPU limit in shares (e.g., '512')")
-	startMount := startFlags.String("mount", "", "Directory path to mount for the job")
-	startWriteBPS := startFlags.String("wbps-limit", "", "Write bytes per second limit (e.g., '1048576' for 1MB/s)")
-	startReadBPS := startFlags.String("rbps-limit", "", "Read bytes per second limit (e.g., '1048576' for 1MB/s)")
+	startMount := startFlags.String("mount", "", "Host directory to bind-mount into the job's root and run in")
+	startNetwork := startFlags.String("network", "host", "Network mode: host, none or bridged")
+	startImage := startFlags.String("image", "", "Image to run the job in, with a copy-on-write root filesystem")
+	startKeepRootfs := startFlags.Bool("keep-rootfs", false, "Keep the changes the job made to its image when it is removed")
+	startLimits := addLimitFlags(startFlags)
 
 	statusFlags := flag.NewFlagSet("status", flag.ExitOnError)
 	statusID := statusFlags.String("id", "", "Job ID")
 
+	waitFlags := flag.NewFlagSet("wait", flag.ExitOnError)
+	waitID := waitFlags.String("id", "", "Job ID")
+	waitTimeout := waitFlags.Duration("timeout", 0, "Give up after this long (e.g., '30s', '5m'); 0 waits forever")
+
 	logsFlags := flag.NewFlagSet("logs", flag.ExitOnError)
 	logsID := logsFlags.String("id", "", "Job ID")
 	logsForce := logsFlags.Bool("force", false, "Stream logs in real-time")
 
 	stopFlags := flag.NewFlagSet("stop", flag.ExitOnError)
 	stopID := stopFlags.String("id", "", "Job ID")
+	stopGrace := stopFlags.Duration("grace", 0, "Time to wait after SIGTERM before sending SIGKILL (e.g., '30s'); 0 uses the server default")
 
 	killFlags := flag.NewFlagSet("kill", flag.ExitOnError)
 	killID := killFlags.String("id", "", "Job ID")
 
+	rmFlags := flag.NewFlagSet("rm", flag.ExitOnError)
+	rmID := rmFlags.String("id", "", "Job ID")
+
+	pruneFlags := flag.NewFlagSet("prune", flag.ExitOnError)
+	pruneOlderThan := pruneFlags.Duration("older-than", 0, "Only remove jobs that finished at least this long ago (e.g., '24h')")
+	pruneStates := pruneFlags.String("state", "", "Comma-separated final states to remove (exited, killed, failed-to-start, oom-killed, lost)")
+
+	updateFlags := flag.NewFlagSet("update", flag.ExitOnError)
+	updateID := updateFlags.String("id", "", "Job ID")
+	updateLimits := addLimitFlags(updateFlags)
+
+	statsFlags := flag.NewFlagSet("stats", flag.ExitOnError)
+	statsID := statsFlags.String("id", "", "Job ID")
+	statsWatch := statsFlags.Bool("watch", false, "Keep printing the statistics until interrupted")
+	statsInterval := statsFlags.Duration("interval", 2*time.Second, "Time between samples with -watch")
+
+	gcFlags := flag.NewFlagSet("gc", flag.ExitOnError)
+	gcRun := gcFlags.Bool("run", false, "Collect orphaned cgroups now instead of showing the latest report")
+
 	// Create a cancellable context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -116,14 +317,19 @@ func main() {
 			log.Fatal("Command is required for start action. Use -cmd flag")
 		}
 
+		jobLimits, err := startLimits.parse()
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		job, err := client.StartJob(ctx, &pb.StartJobRequest{
 			Command:     *startCmd,
 			CommandArgs: startFlags.Args(),
-			MemoryLimit: *startMemLimit,
-			CpuLimit:    *startCPULimit,
 			Mount:       *startMount,
-			WriteBps:    *startWriteBPS,
-			ReadBps:     *startReadBPS,
+			Limits:      protoLimits(jobLimits),
+			Network:     *startNetwork,
+			Image:       *startImage,
+			KeepRootfs:  *startKeepRootfs,
 		})
 		if err != nil {
 			log.Fatalf("Could not start job: %v", err)
@@ -143,11 +349,60 @@ func main() {
 			log.Fatalf("Could not get job status: %v", err)
 		}
 
-		status := "stopped"
+		fmt.Printf("Job status: %s\n", formatState(resp.State))
+		if resp.StartedAt != nil {
+			fmt.Printf("Started:    %s\n", resp.StartedAt.AsTime().Local().Format(time.RFC3339))
+		}
+		if resp.FinishedAt != nil {
+			fmt.Printf("Finished:   %s\n", resp.FinishedAt.AsTime().Local().Format(time.RFC3339))
+		}
+		if resp.ExitCode >= 0 && resp.State == pb.JobState_JOB_STATE_EXITED {
+			fmt.Printf("Exit code:  %d\n", resp.ExitCode)
+		}
+		if resp.Signal != 0 {
+			fmt.Printf("Signal:     %s\n", syscall.Signal(resp.Signal))
+		}
 		if resp.IsRunning {
-			status = "running"
+			if resp.PidsMax != 0 {
+				fmt.Printf("Processes:  %d/%d\n", resp.PidsCurrent, resp.PidsMax)
+			} else {
+				fmt.Printf("Processes:  %d\n", resp.PidsCurrent)
+			}
+			printPressure(resp.Pressure)
+		}
+		if stalls := resp.Stalls; stalls.GetCpu()+stalls.GetMemory()+stalls.GetIo() > 0 {
+			fmt.Printf("Stalls:     cpu %d, memory %d, io %d\n", stalls.GetCpu(), stalls.GetMemory(), stalls.GetIo())
+		}
+		if resp.Error != "" {
+			fmt.Printf("Error:      %s\n", resp.Error)
+		}
+		if resp.OomEvents > 0 || resp.OomKills > 0 {
+			fmt.Printf("OOM:        %d process(es) killed, memory limit reached %d time(s)\n", resp.OomKills, resp.OomEvents)
+		}
+
+	case "wait":
+		if err := waitFlags.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		if *waitID == "" {
+			log.Fatal("Job ID is required for wait action. Use -id flag")
+		}
+
+		req := &pb.WaitJobRequest{JobId: *waitID}
+		if *waitTimeout > 0 {
+			req.Timeout = durationpb.New(*waitTimeout)
+		}
+		resp, err := client.WaitJob(ctx, req)
+		if err != nil {
+			if status.Code(err) == codes.DeadlineExceeded {
+				log.Printf("Timed out waiting for job: %v", err)
+				os.Exit(exitCodeTimeout)
+			}
+			log.Fatalf("Could not wait for job: %v", err)
 		}
-		fmt.Printf("Job status: %s\n", status)
+
+		fmt.Printf("Job status: %s\n", formatState(resp.State))
+		os.Exit(waitExitCode(resp))
 
 	case "logs":
 		if err := logsFlags.Parse(os.Args[2:]); err != nil {
@@ -158,37 +413,31 @@ func main() {
 		}
 
 		stream, err := client.StreamJobLogs(ctx, &pb.JobLogsRequest{
-			JobId: *logsID,
+			JobId:       *logsID,
+			HistoryOnly: !*logsForce,
 		})
 		if err != nil {
 			log.Fatalf("Could not stream logs: %v", err)
 		}
 
 		for {
-			select {
-			case <-ctx.Done():
-			default:
-				output, err := stream.Recv()
-				if err == io.EOF {
-					break
-				}
-				if err != nil {
-					if errors.Is(ctx.Err(), context.Canceled) {
-						return
-					}
-					log.Printf("Error receiving logs: %v", err)
+			output, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				if errors.Is(ctx.Err(), context.Canceled) {
 					return
 				}
-
-				if output.IsStderr {
-					_, _ = fmt.Fprintf(os.Stderr, "%s", output.Data)
-				} else {
-					fmt.Printf("%s", output.Data)
-				}
-			}
-			if !*logsForce {
+				log.Printf("Error receiving logs: %v", err)
 				return
 			}
+
+			if output.IsStderr {
+				_, _ = fmt.Fprintf(os.Stderr, "%s", output.Data)
+			} else {
+				fmt.Printf("%s", output.Data)
+			}
 		}
 	case "list":
 		resp, err := client.ListJobs(ctx, &pb.ListJobsRequest{})
@@ -201,18 +450,13 @@ func main() {
 			return
 		}
 
-		format := "%-40s %-10s %-10s %-15s %-15s %-15s %s\n"
-		fmt.Printf(format, "JOB ID", "STATUS", "MEM-LIMIT", "CPU-LIMIT", "WRITE-BPS", "READ-BPS", "COMMAND")
-		fmt.Println(strings.Repeat("-", 120))
+		format := "%-40s %-12s %-16s %-10s %-15s %-15s %-15s %s\n"
+		fmt.Printf(format, "JOB ID", "OWNER", "STATUS", "MEM-LIMIT", "CPU-LIMIT", "WRITE-BPS", "READ-BPS", "COMMAND")
+		fmt.Println(strings.Repeat("-", 139))
 
 		for _, job := range resp.Jobs {
-			status := "stopped"
-			if job.IsRunning {
-				status = "running"
-			}
-
 			fmt.Printf(format,
-				job.JobId, status, job.MemoryLimit, job.CpuLimit,
+				job.JobId, job.Owner, formatState(job.State), job.MemoryLimit, job.CpuLimit,
 				job.WriteBps, job.ReadBps, job.Command)
 		}
 
@@ -223,9 +467,11 @@ func main() {
 		if *stopID == "" {
 			log.Fatal("Job ID is required for stop action. Use -id flag")
 		}
-		resp, err := client.StopJob(ctx, &pb.StopJobRequest{
-			JobId: *stopID,
-		})
+		req := &pb.StopJobRequest{JobId: *stopID}
+		if *stopGrace > 0 {
+			req.GracePeriod = durationpb.New(*stopGrace)
+		}
+		resp, err := client.StopJob(ctx, req)
 		if err != nil {
 			log.Fatalf("Could not stop job: %v", err)
 		}
@@ -246,6 +492,134 @@ func main() {
 		}
 		fmt.Printf("Kill job result: %s\n", resp.Message)
 
+	case "update":
+		if err := updateFlags.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		if *updateID == "" {
+			log.Fatal("Job ID is required for update action. Use -id flag")
+		}
+		jobLimits, err := updateLimits.parse()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if jobLimits.IsZero() {
+			log.Fatal("At least one limit is required for update action")
+		}
+		resp, err := client.UpdateJobLimits(ctx, &pb.UpdateJobLimitsRequest{
+			JobId:  *updateID,
+			Limits: protoLimits(jobLimits),
+		})
+		if err != nil {
+			log.Fatalf("Could not update job limits: %v", err)
+		}
+		fmt.Printf("Update job result: %s\n", resp.Message)
+
+	case "stats":
+		if err := statsFlags.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		if *statsID == "" {
+			log.Fatal("Job ID is required for stats action. Use -id flag")
+		}
+		if *statsInterval <= 0 {
+			log.Fatal("Interval must be positive")
+		}
+		var prev *pb.JobStatsResponse
+		for {
+			resp, err := client.GetJobStats(ctx, &pb.JobStatsRequest{JobId: *statsID})
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Fatalf("Could not get job stats: %v", err)
+			}
+			printStats(resp, prev)
+			if !*statsWatch {
+				break
+			}
+			prev = resp
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(*statsInterval):
+			}
+			fmt.Println()
+		}
+
+	case "rm":
+		if err := rmFlags.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		if *rmID == "" {
+			log.Fatal("Job ID is required for rm action. Use -id flag")
+		}
+		resp, err := client.RemoveJob(ctx, &pb.RemoveJobRequest{
+			JobId: *rmID,
+		})
+		if err != nil {
+			log.Fatalf("Could not remove job: %v", err)
+		}
+		fmt.Printf("Remove job result: %s\n", resp.Message)
+
+	case "prune":
+		if err := pruneFlags.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		req := &pb.PruneJobsRequest{}
+		if *pruneOlderThan > 0 {
+			req.OlderThan = durationpb.New(*pruneOlderThan)
+		}
+		if *pruneStates != "" {
+			for _, name := range strings.Split(*pruneStates, ",") {
+				state, ok := parseState(strings.TrimSpace(name))
+				if !ok {
+					log.Fatalf("Unknown job state: %s", name)
+				}
+				req.States = append(req.States, state)
+			}
+		}
+		resp, err := client.PruneJobs(ctx, req)
+		if err != nil {
+			log.Fatalf("Could not prune jobs: %v", err)
+		}
+		for _, id := range resp.JobIds {
+			fmt.Println(id)
+		}
+		fmt.Printf("Pruned %d job(s)\n", len(resp.JobIds))
+
+	case "gc":
+		if err := gcFlags.Parse(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		resp, err := client.GetCgroupGCReport(ctx, &pb.CgroupGCReportRequest{Run: *gcRun})
+		if err != nil {
+			log.Fatalf("Could not get cgroup GC report: %v", err)
+		}
+
+		fmt.Printf("Collected at: %s\n", resp.FinishedAt.AsTime().Local().Format(time.RFC3339))
+		if resp.DryRun {
+			fmt.Println("Dry run: orphaned cgroups were not removed")
+		}
+		if resp.Error != "" {
+			fmt.Printf("Error: %s\n", resp.Error)
+		}
+		if len(resp.Orphans) == 0 {
+			fmt.Println("No orphaned cgroups found")
+			return
+		}
+
+		format := "%-60s %-6s %-10s %s\n"
+		fmt.Printf(format, "CGROUP", "PIDS", "ACTION", "ERROR")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, orphan := range resp.Orphans {
+			action := "kept"
+			if orphan.Removed {
+				action = "removed"
+			}
+			fmt.Printf(format, orphan.Path, strconv.Itoa(len(orphan.Pids)), action, orphan.Error)
+		}
+
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		os.Exit(1)
@@ -0,0 +1,39 @@
+package jobmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+// resolveIO returns the IO limits with each device resolved to the
+// "major:minor" of its whole disk. Two limits on the same disk are rejected,
+// since the later io.max write would silently replace the earlier one.
+func resolveIO(ios []limits.IOLimit) ([]limits.IOLimit, error) {
+	resolved := make([]limits.IOLimit, 0, len(ios))
+	requested := make(map[string]string)
+	for _, io := range ios {
+		disk, err := resolveDevice(io.Device)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := requested[disk]; ok {
+			return nil, fmt.Errorf("devices %q and %q are both on disk %s", other, io.Device, disk)
+		}
+		requested[disk] = io.Device
+		io.Device = disk
+		resolved = append(resolved, io)
+	}
+	return resolved, nil
+}
+
+// deviceIDs lists the devices of resolved IO limits, as recorded in
+// Job.DeviceId.
+func deviceIDs(ios []limits.IOLimit) string {
+	devices := make([]string, len(ios))
+	for i, io := range ios {
+		devices[i] = io.Device
+	}
+	return strings.Join(devices, ",")
+}
@@ -0,0 +1,60 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// sysBlockPath is the sysfs directory listing block devices by number. It
+// is a variable so that tests can point it at a fake tree.
+var sysBlockPath = "/sys/dev/block"
+
+// resolveDevice returns the "major:minor" of the whole disk selected by
+// spec: a "major:minor", a device node or a path on a mounted filesystem.
+// An empty spec selects the disk backing the root filesystem.
+func resolveDevice(spec string) (string, error) {
+	number := spec
+	if spec == "" || strings.HasPrefix(spec, "/") {
+		path := spec
+		if path == "" {
+			path = "/"
+		}
+		var stat syscall.Stat_t
+		if err := syscall.Stat(path, &stat); err != nil {
+			return "", fmt.Errorf("failed to stat %s: %v", path, err)
+		}
+		dev := stat.Dev
+		if stat.Mode&syscall.S_IFMT == syscall.S_IFBLK {
+			dev = stat.Rdev
+		}
+		number = fmt.Sprintf("%d:%d", devMajor(dev), devMinor(dev))
+	}
+
+	// /sys/dev/block/<major:minor> links to the device's sysfs directory;
+	// a partition is a subdirectory of its disk and has a partition file.
+	sysPath, err := filepath.EvalSymlinks(filepath.Join(sysBlockPath, number))
+	if err != nil {
+		return "", fmt.Errorf("%s (%s) is not a block device", spec, number)
+	}
+	if _, err := os.Stat(filepath.Join(sysPath, "partition")); err != nil {
+		return number, nil
+	}
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(sysPath), "dev"))
+	if err != nil {
+		return "", fmt.Errorf("failed to find the disk of partition %s: %v", number, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// devMajor and devMinor split a device number as glibc's major(3) and
+// minor(3) do.
+func devMajor(dev uint64) uint64 {
+	return (dev>>8)&0xfff | (dev>>32)&^0xfff
+}
+
+func devMinor(dev uint64) uint64 {
+	return dev&0xff | (dev>>12)&^0xff
+}
@@ -0,0 +1,82 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+// fakeSysBlock builds a sysfs tree with an NVMe disk and its partition and
+// a device-mapper volume.
+func fakeSysBlock(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	devices := map[string]string{
+		"devices/nvme0n1":           "259:0",
+		"devices/nvme0n1/nvme0n1p1": "259:1",
+		"devices/dm-0":              "253:0",
+	}
+	block := filepath.Join(root, "dev", "block")
+	if err := os.MkdirAll(block, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for dir, number := range devices {
+		path := filepath.Join(root, dir)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "dev"), []byte(number+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(path, filepath.Join(block, number)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "devices/nvme0n1/nvme0n1p1/partition"), []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := sysBlockPath
+	sysBlockPath = block
+	t.Cleanup(func() { sysBlockPath = saved })
+}
+
+func TestResolveDevice(t *testing.T) {
+	fakeSysBlock(t)
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"259:1", "259:0", false},
+		{"259:0", "259:0", false},
+		{"253:0", "253:0", false},
+		{"8:0", "", true},
+		{"/does/not/exist", "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveDevice(tt.spec)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("resolveDevice(%q) = (%q, %v), want %q (error %v)", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResolveIORejectsSameDiskTwice(t *testing.T) {
+	fakeSysBlock(t)
+
+	ios, err := resolveIO([]limits.IOLimit{{Device: "259:1", WriteBps: 1}, {Device: "253:0", ReadIOPS: 10}})
+	if err != nil {
+		t.Fatalf("resolveIO() error = %v", err)
+	}
+	if got := deviceIDs(ios); got != "259:0,253:0" {
+		t.Fatalf("devices = %q, want 259:0,253:0", got)
+	}
+
+	if _, err := resolveIO([]limits.IOLimit{{Device: "259:0", WriteBps: 1}, {Device: "259:1", ReadBps: 1}}); err == nil {
+		t.Fatal("resolveIO() accepted two limits on the same disk")
+	}
+}
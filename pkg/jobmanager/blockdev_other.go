@@ -0,0 +1,10 @@
+//go:build !linux
+
+package jobmanager
+
+import "errors"
+
+// resolveDevice reports that IO limits need Linux block devices.
+func resolveDevice(spec string) (string, error) {
+	return "", errors.New("IO limits require Linux block devices")
+}
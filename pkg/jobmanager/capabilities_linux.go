@@ -0,0 +1,77 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// keptCapabilities are the capabilities isolated jobs keep: Docker's
+// default set without CAP_MKNOD. All others are dropped, notably
+// CAP_SYS_ADMIN, so jobs cannot change their mounts, CAP_MKNOD and
+// CAP_SYS_RAWIO, so they cannot reach host devices, CAP_SYS_MODULE,
+// CAP_NET_ADMIN and CAP_DAC_READ_SEARCH, which allows opening files by
+// handle outside the job's root.
+var keptCapabilities = map[int]bool{
+	unix.CAP_CHOWN:            true,
+	unix.CAP_DAC_OVERRIDE:     true,
+	unix.CAP_FOWNER:           true,
+	unix.CAP_FSETID:           true,
+	unix.CAP_KILL:             true,
+	unix.CAP_SETGID:           true,
+	unix.CAP_SETUID:           true,
+	unix.CAP_SETPCAP:          true,
+	unix.CAP_NET_BIND_SERVICE: true,
+	unix.CAP_NET_RAW:          true,
+	unix.CAP_SYS_CHROOT:       true,
+	unix.CAP_AUDIT_WRITE:      true,
+	unix.CAP_SETFCAP:          true,
+}
+
+// lastCapability returns the highest capability the kernel knows.
+func lastCapability() (int, error) {
+	data, err := os.ReadFile("/proc/sys/kernel/cap_last_cap")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// dropCapabilities removes every capability but keptCapabilities from the
+// bounding, ambient, inheritable, permitted and effective sets of the
+// calling thread. A root process executed afterwards gets its bounding set
+// as permitted set, so the dropped capabilities stay gone.
+func dropCapabilities() error {
+	last, err := lastCapability()
+	if err != nil {
+		return fmt.Errorf("failed to read the last capability: %v", err)
+	}
+
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	if err := unix.Capget(&header, &data[0]); err != nil {
+		return fmt.Errorf("failed to read capabilities: %v", err)
+	}
+	for c := 0; c <= last; c++ {
+		if keptCapabilities[c] {
+			continue
+		}
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(c), 0, 0, 0); err != nil {
+			return fmt.Errorf("failed to drop capability %d: %v", c, err)
+		}
+		bit := uint32(1) << (c % 32)
+		data[c/32].Effective &^= bit
+		data[c/32].Permitted &^= bit
+		data[c/32].Inheritable &^= bit
+	}
+	if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to clear ambient capabilities: %v", err)
+	}
+	if err := unix.Capset(&header, &data[0]); err != nil {
+		return fmt.Errorf("failed to drop capabilities: %v", err)
+	}
+	return nil
+}
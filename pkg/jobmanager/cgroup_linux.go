@@ -0,0 +1,14 @@
+package jobmanager
+
+import (
+	"os"
+	"syscall"
+)
+
+// useCgroup makes the process started with attr begin its life in the
+// cgroup open as dir, using clone3 with CLONE_INTO_CGROUP.
+func useCgroup(attr *syscall.SysProcAttr, dir *os.File) error {
+	attr.UseCgroupFD = true
+	attr.CgroupFD = int(dir.Fd())
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package jobmanager
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// useCgroup reports that resource limits need Linux cgroups.
+func useCgroup(attr *syscall.SysProcAttr, dir *os.File) error {
+	return errors.New("resource limits require Linux cgroups")
+}
@@ -0,0 +1,182 @@
+package jobmanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultCgroupGCInterval is how often orphaned cgroups are collected when
+// no interval is configured.
+const DefaultCgroupGCInterval = 5 * time.Minute
+
+// cgroupDrainTimeout bounds how long the collector waits for the members of
+// an orphaned cgroup to die before removing it.
+const cgroupDrainTimeout = time.Second
+
+// OrphanedCgroup is a sentry-run cgroup that no live job owns.
+type OrphanedCgroup struct {
+	Path  string
+	JobID string
+	// PIDs are the members of the cgroup when it was found.
+	PIDs []int
+	// Removed is true when the members were killed and the cgroup deleted.
+	Removed bool
+	// Error describes why the cgroup could not be removed.
+	Error string
+}
+
+// CgroupGCReport describes one pass of the orphaned cgroup collector.
+type CgroupGCReport struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// DryRun is true when orphans were only reported, not removed.
+	DryRun  bool
+	Orphans []OrphanedCgroup
+	// Error describes why the cgroup hierarchy could not be scanned.
+	Error string
+}
+
+// CollectOrphanedCgroups scans the cgroup hierarchy for sentry-run cgroups
+// that do not belong to a live job, kills their members and removes them.
+// In dry-run mode the orphans are only reported. The report is also kept
+// for LastCgroupGCReport.
+func (m *JobManager) CollectOrphanedCgroups() CgroupGCReport {
+	report := CgroupGCReport{StartedAt: time.Now(), DryRun: m.cgroupGCDryRun}
+
+	entries, err := os.ReadDir(cgroupBasePath)
+	if err != nil {
+		report.Error = fmt.Sprintf("failed to read %s: %v", cgroupBasePath, err)
+	}
+
+	prefix := cgroupName + "-"
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		jobID := strings.TrimPrefix(entry.Name(), prefix)
+		if m.ownsCgroup(jobID) {
+			continue
+		}
+
+		orphan := OrphanedCgroup{Path: filepath.Join(cgroupBasePath, entry.Name()), JobID: jobID}
+		orphan.PIDs, _ = cgroupMembers(orphan.Path)
+		if !report.DryRun {
+			if err := removeCgroup(orphan.Path, orphan.PIDs); err != nil {
+				orphan.Error = err.Error()
+				logger.Warn("failed to remove orphaned cgroup", "path", orphan.Path, "error", err)
+			} else {
+				orphan.Removed = true
+				logger.Info("removed orphaned cgroup", "path", orphan.Path, "killed", len(orphan.PIDs))
+			}
+		} else {
+			logger.Info("found orphaned cgroup", "path", orphan.Path, "pids", len(orphan.PIDs))
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+	report.FinishedAt = time.Now()
+
+	m.gcMu.Lock()
+	m.lastGCReport = &report
+	m.gcMu.Unlock()
+	return report
+}
+
+// LastCgroupGCReport returns the report of the latest collector pass, if any.
+func (m *JobManager) LastCgroupGCReport() (CgroupGCReport, bool) {
+	m.gcMu.Lock()
+	defer m.gcMu.Unlock()
+	if m.lastGCReport == nil {
+		return CgroupGCReport{}, false
+	}
+	return *m.lastGCReport, true
+}
+
+// RunCgroupGC collects orphaned cgroups immediately and then periodically
+// until ctx is done. It must be called after Recover so that re-adopted
+// jobs keep their cgroups.
+func (m *JobManager) RunCgroupGC(ctx context.Context) {
+	m.CollectOrphanedCgroups()
+	if m.cgroupGCInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.cgroupGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.CollectOrphanedCgroups()
+		}
+	}
+}
+
+// ownsCgroup reports whether the cgroup of jobID belongs to a job that is
+// still being set up, running or reaped by its owner goroutine.
+func (m *JobManager) ownsCgroup(jobID string) bool {
+	value, exists := m.jobs.Load(jobID)
+	if !exists {
+		return false
+	}
+	select {
+	case <-value.(*Job).done:
+		return false
+	default:
+		return true
+	}
+}
+
+// cgroupMembers returns the PIDs listed in a cgroup's cgroup.procs.
+func cgroupMembers(path string) ([]int, error) {
+	data, err := os.ReadFile(filepath.Join(path, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, field := range bytes.Fields(data) {
+		if pid, err := strconv.Atoi(string(field)); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// removeCgroup kills the members of a cgroup and deletes it.
+func removeCgroup(path string, pids []int) error {
+	if len(pids) > 0 {
+		if err := os.WriteFile(filepath.Join(path, "cgroup.kill"), []byte("1"), 0644); err != nil {
+			for _, pid := range pids {
+				if killErr := syscall.Kill(pid, syscall.SIGKILL); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
+					return fmt.Errorf("failed to kill process %d: %v", pid, killErr)
+				}
+			}
+		}
+
+		deadline := time.Now().Add(cgroupDrainTimeout)
+		for {
+			members, err := cgroupMembers(path)
+			if err != nil || len(members) == 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%d processes still running after kill", len(members))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if err := syscall.Rmdir(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove cgroup directory %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func fakeCgroupRoot(t *testing.T, groups ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, group := range groups {
+		if err := os.Mkdir(filepath.Join(root, group), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := cgroupBasePath
+	cgroupBasePath = root
+	t.Cleanup(func() { cgroupBasePath = saved })
+	return root
+}
+
+func TestCollectOrphanedCgroups(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-live", "sentry-run-orphan", "system.slice")
+
+	manager := New()
+	manager.jobs.Store("live", &Job{ID: "live", done: make(chan struct{})})
+
+	report := manager.CollectOrphanedCgroups()
+	if report.Error != "" {
+		t.Fatalf("report error = %s", report.Error)
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0].JobID != "orphan" || !report.Orphans[0].Removed {
+		t.Fatalf("orphans = %+v, want orphan removed", report.Orphans)
+	}
+
+	for group, want := range map[string]bool{"sentry-run-live": true, "sentry-run-orphan": false, "system.slice": true} {
+		if _, err := os.Stat(filepath.Join(root, group)); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", group, err == nil, want)
+		}
+	}
+
+	if last, ok := manager.LastCgroupGCReport(); !ok || len(last.Orphans) != 1 {
+		t.Fatalf("LastCgroupGCReport() = %+v, %v", last, ok)
+	}
+}
+
+func TestCollectOrphanedCgroupsDryRun(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-orphan")
+
+	report := New(WithCgroupGCDryRun(true)).CollectOrphanedCgroups()
+	if !report.DryRun || len(report.Orphans) != 1 || report.Orphans[0].Removed {
+		t.Fatalf("dry-run report = %+v, want one orphan left in place", report)
+	}
+	if _, err := os.Stat(filepath.Join(root, "sentry-run-orphan")); err != nil {
+		t.Fatalf("dry run removed the orphaned cgroup: %v", err)
+	}
+}
+
+func TestFinishedJobDoesNotOwnCgroup(t *testing.T) {
+	fakeCgroupRoot(t, "sentry-run-done")
+
+	done := make(chan struct{})
+	close(done)
+	manager := New()
+	manager.jobs.Store("done", &Job{ID: "done", done: done})
+
+	if report := manager.CollectOrphanedCgroups(); len(report.Orphans) != 1 {
+		t.Fatalf("orphans = %+v, want the finished job's cgroup", report.Orphans)
+	}
+}
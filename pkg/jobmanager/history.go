@@ -0,0 +1,219 @@
+package jobmanager
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// OutputReader reads the output of one job stream as it was when the
+// reader was opened: a prefix of a file followed by bytes held in memory.
+type OutputReader struct {
+	file     *os.File
+	fileSize int64
+	mem      []byte
+	offset   int64
+}
+
+// NewOutputReader returns a reader of output held in memory.
+func NewOutputReader(data []byte) *OutputReader {
+	return &OutputReader{mem: data}
+}
+
+// Size returns the number of bytes the reader covers.
+func (r *OutputReader) Size() int64 {
+	return r.fileSize + int64(len(r.mem))
+}
+
+func (r *OutputReader) Read(p []byte) (int, error) {
+	if r.offset < r.fileSize {
+		p = p[:min(int64(len(p)), r.fileSize-r.offset)]
+		n, err := r.file.ReadAt(p, r.offset)
+		r.offset += int64(n)
+		if err == io.EOF && r.offset < r.fileSize {
+			err = io.ErrUnexpectedEOF
+		} else if err == io.EOF {
+			err = nil
+		}
+		return n, err
+	}
+	if r.offset >= r.Size() {
+		return 0, io.EOF
+	}
+	n := copy(p, r.mem[r.offset-r.fileSize:])
+	r.offset += int64(n)
+	return n, nil
+}
+
+// Seek sets the offset of the next Read.
+func (r *OutputReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.Size()
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid output offset %d", offset)
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// Close closes the file the reader reads from.
+func (r *OutputReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+// outputHistory keeps the most recent output of one job stream in a ring
+// buffer of at most limit bytes. Older output evicted from the ring is
+// appended to a spill file when one is configured and dropped otherwise.
+// A limit of zero or less keeps the whole output in memory.
+//
+// outputHistory is not safe for concurrent use; the job mutex guards it.
+type outputHistory struct {
+	limit int
+	buf   []byte
+	head  int
+	size  int
+
+	spillPath string
+	spill     *os.File
+	spilled   int64
+	dropped   int64
+}
+
+func newOutputHistory(limit int, spillPath string) *outputHistory {
+	return &outputHistory{limit: limit, spillPath: spillPath}
+}
+
+// Write appends p to the history, evicting the oldest output if needed.
+func (h *outputHistory) Write(p []byte) (int, error) {
+	n := len(p)
+	if h.limit <= 0 {
+		h.buf = append(h.buf, p...)
+		h.size = len(h.buf)
+		return n, nil
+	}
+
+	if len(p) >= h.limit {
+		h.evict(h.size)
+		h.spillOut(p[:len(p)-h.limit])
+		p = p[len(p)-h.limit:]
+	} else if over := h.size + len(p) - h.limit; over > 0 {
+		h.evict(over)
+	}
+	h.push(p)
+	return n, nil
+}
+
+// push stores p in the ring; the caller guarantees it fits.
+func (h *outputHistory) push(p []byte) {
+	// The buffer grows lazily and only wraps once it reached the limit
+	if len(h.buf) < h.limit {
+		h.buf = append(h.buf, p...)
+		h.size += len(p)
+		return
+	}
+
+	tail := (h.head + h.size) % h.limit
+	copied := copy(h.buf[tail:], p)
+	copy(h.buf, p[copied:])
+	h.size += len(p)
+}
+
+// evict moves the oldest k bytes of the ring to the spill tier.
+func (h *outputHistory) evict(k int) {
+	if k <= 0 {
+		return
+	}
+	if len(h.buf) < h.limit {
+		h.buf = append(h.buf, make([]byte, h.limit-len(h.buf))...)
+	}
+
+	first := min(k, h.limit-h.head)
+	h.spillOut(h.buf[h.head : h.head+first])
+	h.spillOut(h.buf[:k-first])
+	h.head = (h.head + k) % h.limit
+	h.size -= k
+}
+
+func (h *outputHistory) spillOut(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	if h.spillPath == "" {
+		h.dropped += int64(len(p))
+		return
+	}
+
+	if h.spill == nil {
+		f, err := os.OpenFile(h.spillPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			logger.Warn("failed to open output spill file, dropping output", "path", h.spillPath, "error", err)
+			h.spillPath = ""
+			h.dropped += int64(len(p))
+			return
+		}
+		h.spill = f
+	}
+
+	n, err := h.spill.Write(p)
+	h.spilled += int64(n)
+	if err != nil {
+		logger.Warn("failed to spill output", "path", h.spillPath, "error", err)
+		h.dropped += int64(len(p) - n)
+	}
+}
+
+// reader returns a reader of the output retained so far, spilled part
+// first. The spill file is read in place and only the ring is copied, so
+// the reader holds at most limit bytes in memory.
+func (h *outputHistory) reader() (*OutputReader, error) {
+	r := &OutputReader{}
+	if h.spilled > 0 {
+		f, err := os.Open(h.spillPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open output spill file: %v", err)
+		}
+		r.file, r.fileSize = f, h.spilled
+	}
+
+	if h.limit <= 0 || len(h.buf) < h.limit {
+		r.mem = append([]byte(nil), h.buf[h.head:h.head+h.size]...)
+		return r, nil
+	}
+	first := min(h.size, h.limit-h.head)
+	r.mem = make([]byte, 0, h.size)
+	r.mem = append(r.mem, h.buf[h.head:h.head+first]...)
+	r.mem = append(r.mem, h.buf[:h.size-first]...)
+	return r, nil
+}
+
+// Dropped returns how many bytes of output were discarded.
+func (h *outputHistory) Dropped() int64 {
+	return h.dropped
+}
+
+// closeSpill closes the spill file once no more output will be written.
+func (h *outputHistory) closeSpill() {
+	if h.spill != nil {
+		if err := h.spill.Close(); err != nil {
+			logger.Warn("failed to close output spill file", "path", h.spillPath, "error", err)
+		}
+		h.spill = nil
+	}
+}
+
+// release closes and deletes the spill file.
+func (h *outputHistory) release() {
+	h.closeSpill()
+	if h.spilled > 0 {
+		if err := os.Remove(h.spillPath); err != nil && !os.IsNotExist(err) {
+			logger.Warn("failed to remove output spill file", "path", h.spillPath, "error", err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package jobmanager
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func historyBytes(t *testing.T, h *outputHistory) string {
+	t.Helper()
+	r, err := h.reader()
+	if err != nil {
+		t.Fatalf("reader() error = %v", err)
+	}
+	defer r.Close()
+	return readAll(t, r)
+}
+
+func readAll(t *testing.T, r io.Reader) string {
+	t.Helper()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	return string(out)
+}
+
+func TestOutputHistoryUnbounded(t *testing.T) {
+	h := newOutputHistory(0, "")
+	for _, chunk := range []string{"hello ", "world"} {
+		if _, err := h.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if got := historyBytes(t, h); got != "hello world" {
+		t.Fatalf("history = %q, want %q", got, "hello world")
+	}
+}
+
+func TestOutputHistoryKeepsMostRecentBytes(t *testing.T) {
+	h := newOutputHistory(8, "")
+	var all strings.Builder
+	for _, chunk := range []string{"abc", "defg", "hi", "jklmn", "o", "pqrstuvwxyz"} {
+		_, _ = h.Write([]byte(chunk))
+		all.WriteString(chunk)
+		want := all.String()
+		if len(want) > 8 {
+			want = want[len(want)-8:]
+		}
+		if got := historyBytes(t, h); got != want {
+			t.Fatalf("after %q history = %q, want %q", chunk, got, want)
+		}
+	}
+	if got, want := h.Dropped(), int64(all.Len()-8); got != want {
+		t.Fatalf("Dropped() = %d, want %d", got, want)
+	}
+}
+
+func TestOutputHistorySpillsToDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job.stdout.log")
+	h := newOutputHistory(16, path)
+
+	var all bytes.Buffer
+	for i := 0; i < 100; i++ {
+		chunk := []byte(strings.Repeat(string(rune('a'+i%26)), i%7+1))
+		_, _ = h.Write(chunk)
+		all.Write(chunk)
+	}
+	if got := historyBytes(t, h); got != all.String() {
+		t.Fatalf("history = %q, want %q", got, all.String())
+	}
+	if h.Dropped() != 0 {
+		t.Fatalf("Dropped() = %d, want 0 with spilling", h.Dropped())
+	}
+
+	h.closeSpill()
+	if got := historyBytes(t, h); got != all.String() {
+		t.Fatal("history changed after closing the spill file")
+	}
+
+	h.release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("spill file still exists after release: %v", err)
+	}
+}
+
+func TestOutputReaderIsSnapshot(t *testing.T) {
+	h := newOutputHistory(4, filepath.Join(t.TempDir(), "job.stdout.log"))
+	_, _ = h.Write([]byte("spilled-ring"))
+
+	r, err := h.reader()
+	if err != nil {
+		t.Fatalf("reader() error = %v", err)
+	}
+	defer r.Close()
+	// Output written after the reader was opened is not part of it
+	_, _ = h.Write([]byte("-later"))
+
+	if r.Size() != int64(len("spilled-ring")) {
+		t.Fatalf("Size() = %d, want %d", r.Size(), len("spilled-ring"))
+	}
+	buf := make([]byte, 5)
+	if n, err := r.Read(buf); err != nil || string(buf[:n]) != "spill" {
+		t.Fatalf("Read() = (%q, %v), want bounded read of the spill file", buf[:n], err)
+	}
+	if _, err := r.Seek(-6, io.SeekEnd); err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+	if got := readAll(t, r); got != "d-ring" {
+		t.Fatalf("tail = %q, want %q", got, "d-ring")
+	}
+}
+
+func TestOutputHistoryDoesNotSpillWithinLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job.stdout.log")
+	h := newOutputHistory(16, path)
+	_, _ = h.Write([]byte("short"))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("spill file created for output within the limit: %v", err)
+	}
+}
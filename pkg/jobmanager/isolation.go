@@ -0,0 +1,41 @@
+package jobmanager
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// initArg0 is the argv[0] under which the server binary re-executes itself
+// as the init of an isolated job.
+const initArg0 = "sentry-job-init"
+
+// initConfig tells the init of an isolated job how to set up its namespaces
+// before it executes the job command.
+type initConfig struct {
+	Hostname string      `json:"hostname"`
+	Rootfs   *initRootfs `json:"rootfs,omitempty"`
+	Network  initNetwork `json:"network"`
+	// Hidden are the host directories covered with an empty tmpfs in a job
+	// that keeps the host's root filesystem
+	Hidden []string `json:"hidden,omitempty"`
+}
+
+// readInitStatus reads the wait status of the job command that the init of
+// an isolated job reported on initStatus before exiting, and closes it. The
+// init exits normally even when the command was killed, so its own status
+// does not say how the command ended. It reports false if the init died
+// before writing the status.
+func readInitStatus(initStatus *os.File) (syscall.WaitStatus, bool) {
+	defer initStatus.Close()
+	data, err := io.ReadAll(initStatus)
+	if err != nil {
+		return 0, false
+	}
+	status, err := strconv.ParseUint(string(data), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return syscall.WaitStatus(status), true
+}
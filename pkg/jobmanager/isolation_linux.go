@@ -0,0 +1,244 @@
+package jobmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+)
+
+// namespaceFlags are the namespaces an isolated job is started in.
+const namespaceFlags = syscall.CLONE_NEWPID | syscall.CLONE_NEWNS | syscall.CLONE_NEWUTS | syscall.CLONE_NEWIPC
+
+// initErrorFD is the descriptor on which the init reports why it could not
+// start the job, initReadyFD the one on which the server signals, by closing
+// it, that the job's network is ready, and initStatusFD the one on which the
+// init reports the wait status of the job command. They are the command's
+// ExtraFiles.
+const (
+	initErrorFD  = 3
+	initReadyFD  = 4
+	initStatusFD = 5
+)
+
+// forwardedSignals are the signals the init passes on to the job command.
+// The kernel drops signals to the init of a PID namespace that it has no
+// handler for, so the init handles them on the command's behalf.
+var forwardedSignals = []os.Signal{
+	syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP, syscall.SIGQUIT,
+	syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGWINCH,
+}
+
+func init() {
+	if len(os.Args) > 2 && os.Args[0] == initArg0 {
+		runInit(os.Args[1], os.Args[2:])
+	}
+}
+
+// isolatedCommand returns a command that runs command in new PID, mount,
+// UTS and IPC namespaces, and a new network namespace unless the job uses
+// the host network. The server binary is re-executed as the job's init,
+// which prepares the namespaces as described by config and then runs the
+// command as its child, staying PID 1 to forward signals and reap orphans.
+func isolatedCommand(config initConfig, command string, args []string) *exec.Cmd {
+	rawConfig, _ := json.Marshal(config)
+	cmd := exec.Command("/proc/self/exe", append([]string{string(rawConfig), command}, args...)...)
+	cmd.Args[0] = initArg0
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Cloneflags: namespaceFlags}
+	if config.Network.Mode != NetworkHost {
+		cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+	return cmd
+}
+
+// startIsolated starts a command built by isolatedCommand, calls prepare
+// with the PID of its init, if set, and waits until the init has started
+// the job command. It returns the init's error if the namespaces could not
+// be set up or the command could not be executed, and otherwise the pipe
+// from which readInitStatus reads the command's wait status.
+func startIsolated(cmd *exec.Cmd, prepare func(pid int) error) (*os.File, error) {
+	initErrors, childErrors, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create init pipe: %v", err)
+	}
+	defer initErrors.Close()
+	childReady, initReady, err := os.Pipe()
+	if err != nil {
+		childErrors.Close()
+		return nil, fmt.Errorf("failed to create init pipe: %v", err)
+	}
+	defer initReady.Close()
+	initStatus, childStatus, err := os.Pipe()
+	if err != nil {
+		childErrors.Close()
+		childReady.Close()
+		return nil, fmt.Errorf("failed to create init pipe: %v", err)
+	}
+
+	cmd.ExtraFiles = []*os.File{childErrors, childReady, childStatus}
+	err = cmd.Start()
+	childErrors.Close()
+	childReady.Close()
+	childStatus.Close()
+	if err != nil {
+		initStatus.Close()
+		return nil, err
+	}
+
+	if prepare != nil {
+		if err := prepare(cmd.Process.Pid); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			initStatus.Close()
+			return nil, err
+		}
+	}
+	initReady.Close()
+
+	// The init closes its end of the pipe once the command has started
+	msg, _ := io.ReadAll(initErrors)
+	if len(msg) == 0 {
+		return initStatus, nil
+	}
+	cmd.Wait()
+	initStatus.Close()
+	return nil, errors.New(string(msg))
+}
+
+// runInit prepares the namespaces of an isolated job and runs its command.
+// It runs as PID 1 of the job's PID namespace and never returns.
+func runInit(rawConfig string, argv []string) {
+	// Capabilities are per thread, so they are dropped on the thread that
+	// starts the command
+	runtime.LockOSThread()
+	initErrors := os.NewFile(initErrorFD, "init-errors")
+	fail := func(err error) {
+		fmt.Fprint(initErrors, err)
+		os.Exit(127)
+	}
+
+	var config initConfig
+	if err := json.Unmarshal([]byte(rawConfig), &config); err != nil {
+		fail(fmt.Errorf("invalid init config: %v", err))
+	}
+	if err := setupNamespaces(config); err != nil {
+		fail(err)
+	}
+
+	// Wait until the server has moved the job's network link in
+	ready := os.NewFile(initReadyFD, "init-ready")
+	io.Copy(io.Discard, ready)
+	ready.Close()
+	if err := setupNetwork(config.Network); err != nil {
+		fail(err)
+	}
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		fail(err)
+	}
+
+	if err := dropCapabilities(); err != nil {
+		fail(err)
+	}
+	// Signals are caught before the command starts, so none arrive while
+	// the init would still drop them
+	signals := make(chan os.Signal, 16)
+	signal.Notify(signals, forwardedSignals...)
+	children := make(chan os.Signal, 1)
+	signal.Notify(children, syscall.SIGCHLD)
+	// The descriptors from the server are not close-on-exec in the init
+	syscall.CloseOnExec(initErrorFD)
+	syscall.CloseOnExec(initStatusFD)
+	command, err := os.StartProcess(path, argv, &os.ProcAttr{
+		Env:   os.Environ(),
+		Files: []*os.File{os.Stdin, os.Stdout, os.Stderr},
+	})
+	if err != nil {
+		fail(err)
+	}
+	initErrors.Close()
+
+	status := superviseCommand(command.Pid, signals, children)
+	fmt.Fprint(os.NewFile(initStatusFD, "init-status"), uint32(status))
+	if status.Signaled() {
+		// PID 1 cannot be killed by its own signals
+		os.Exit(128 + int(status.Signal()))
+	}
+	os.Exit(status.ExitStatus())
+}
+
+// superviseCommand forwards signals to the job command and reaps every
+// process reparented to the init until the command exits, and returns the
+// command's wait status.
+func superviseCommand(pid int, signals, children <-chan os.Signal) syscall.WaitStatus {
+	for {
+		select {
+		case sig := <-signals:
+			syscall.Kill(pid, sig.(syscall.Signal))
+		case <-children:
+			// SIGCHLD is not queued, so one may stand for several children
+			for {
+				var status syscall.WaitStatus
+				reaped, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+				if err == syscall.EINTR {
+					continue
+				}
+				if err != nil || reaped <= 0 {
+					break
+				}
+				if reaped == pid {
+					return status
+				}
+			}
+		}
+	}
+}
+
+// setupNamespaces keeps the job's mounts out of the host, names its UTS
+// namespace after the job and mounts a /proc that only shows the job's
+// processes, in the job's own root filesystem if it has one. Jobs that keep
+// the host's root see it read-only, without the hidden directories, and get
+// an empty /sys/fs/cgroup, so they cannot write to the cgroups of other jobs
+// or the server.
+func setupNamespaces(config initConfig) error {
+	// The init is unshared from the server's cgroup namespace here rather
+	// than at clone, so the namespace is rooted at the job's own cgroup
+	if err := syscall.Unshare(syscall.CLONE_NEWCGROUP); err != nil {
+		return fmt.Errorf("failed to create cgroup namespace: %v", err)
+	}
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %v", err)
+	}
+	if err := syscall.Sethostname([]byte(config.Hostname)); err != nil {
+		return fmt.Errorf("failed to set hostname: %v", err)
+	}
+
+	if config.Rootfs != nil {
+		return setupRootfs(*config.Rootfs, config.Network.ResolvConf)
+	}
+	if err := protectHostRoot(config.Hidden); err != nil {
+		return err
+	}
+	if err := syscall.Mount("proc", "/proc", "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("failed to mount /proc: %v", err)
+	}
+	// The host's resolv.conf is only covered in the job's mount namespace;
+	// a host without one is left alone
+	if _, err := os.Stat("/etc/resolv.conf"); err == nil && config.Network.ResolvConf != "" {
+		if err := bindReadOnly(config.Network.ResolvConf, "/etc/resolv.conf"); err != nil {
+			return err
+		}
+	}
+	if _, err := os.Stat(cgroupBasePath); err != nil {
+		return nil
+	}
+	if err := syscall.Mount("tmpfs", cgroupBasePath, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC|syscall.MS_RDONLY, "size=0"); err != nil {
+		return fmt.Errorf("failed to hide %s: %v", cgroupBasePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+func TestIsolatedJobHasOwnPIDNamespace(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	manager := New(WithIsolation(true))
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "echo $$ $(hostname); echo /proc/[0-9]*"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	<-job.Done()
+
+	if status := job.Status(); status.State != StateExited || status.ExitCode != 0 {
+		t.Fatalf("status = %+v, want exited 0", status)
+	}
+	if !job.Isolated {
+		t.Error("job.Isolated = false, want true")
+	}
+	stdout, stderr, err := manager.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() error = %v", err)
+	}
+	// Only the init and the job's shell are visible in its /proc
+	var pid int
+	fmt.Sscan(string(stdout), &pid)
+	if want := fmt.Sprintf("%d %s\n/proc/1 /proc/%d\n", pid, job.ID, pid); pid <= 1 || string(stdout) != want {
+		t.Fatalf("stdout = %q, want %q (stderr %q)", stdout, want, stderr)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostname == job.ID {
+		t.Fatal("job changed the host's hostname")
+	}
+}
+
+func TestStopIsolatedJob(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	manager := New(WithIsolation(true))
+	// sleep installs no SIGTERM handler, so it is only stopped before the
+	// grace period if the init is not the one receiving the signal. In its
+	// own session it only gets the SIGTERM the init forwards.
+	for _, args := range [][]string{{"sleep", "30"}, {"setsid", "sleep", "30"}} {
+		job, err := manager.StartJob("alice", args[0], args[1:], limits.Limits{}, StartOptions{})
+		if err != nil {
+			t.Fatalf("StartJob(%v) error = %v", args, err)
+		}
+
+		start := time.Now()
+		result, err := manager.StopJob(job.ID, 10*time.Second)
+		if err != nil {
+			t.Fatalf("StopJob(%v) error = %v", args, err)
+		}
+		if result.Escalated || time.Since(start) > 5*time.Second {
+			t.Fatalf("StopJob(%v) = %+v after %v, want a stop without escalation", args, result, time.Since(start))
+		}
+		if status := result.Status; status.State != StateKilled || status.Signal != syscall.SIGTERM || status.ExitCode != -1 {
+			t.Fatalf("%v status = %+v, want killed by SIGTERM", args, status)
+		}
+	}
+}
+
+func TestIsolatedJobReportsExitCode(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	manager := New(WithIsolation(true))
+	// The orphaned sleep is reaped by the init and killed with the namespace
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "(sleep 30 &); exit 3"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	select {
+	case <-job.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("job did not finish after its command exited")
+	}
+	if status := job.Status(); status.State != StateExited || status.ExitCode != 3 {
+		t.Fatalf("status = %+v, want exited with code 3", status)
+	}
+}
+
+func TestIsolatedJobSeesHostRootReadOnly(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	// The job gets its own /tmp, so the directories live elsewhere
+	dir, err := os.MkdirTemp(".", ".hostroot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private := filepath.Join(dir, "private")
+	if err := os.Mkdir(private, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(private, "server.key"), []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := New(WithIsolation(true), WithProtectedPaths(private))
+	script := fmt.Sprintf(`touch %[1]s/written 2>/dev/null && echo host-writable
+ls -A %[2]s
+cat %[2]s/server.key 2>/dev/null
+touch %[2]s/written 2>/dev/null && echo private-writable
+touch /tmp/scratch && ls -A /tmp
+echo done`, dir, private)
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", script}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	<-job.Done()
+
+	stdout, stderr, err := manager.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() error = %v", err)
+	}
+	if want := "scratch\ndone\n"; string(stdout) != want {
+		t.Fatalf("stdout = %q, want %q (stderr %q)", stdout, want, stderr)
+	}
+	for _, path := range []string{filepath.Join(dir, "written"), filepath.Join(private, "written")} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("job created %s on the host", path)
+		}
+	}
+	if _, err := os.Stat("/tmp/scratch"); err == nil {
+		t.Error("job wrote to the host's /tmp")
+	}
+}
+
+func TestIsolatedJobFailsToStart(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	manager := New(WithIsolation(true))
+	if _, err := manager.StartJob("alice", "/nonexistent/command", nil, limits.Limits{}, StartOptions{}); err == nil || !strings.Contains(err.Error(), "/nonexistent/command") {
+		t.Fatalf("StartJob() error = %v, want error naming the command", err)
+	}
+
+	jobs := manager.ListJobs()
+	if len(jobs) != 1 || jobs[0].Status().State != StateFailedToStart {
+		t.Fatalf("ListJobs() = %v, want one failed-to-start job", jobs)
+	}
+}
+
+func TestIsolatedJobDropsCapabilities(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	var kept uint64
+	for c := range keptCapabilities {
+		kept |= 1 << c
+	}
+	manager := New(WithIsolation(true))
+	script := `grep -E '^Cap(Bnd|Eff|Prm):' /proc/self/status | cut -f2
+mount -t tmpfs x /mnt 2>/dev/null && echo mounted
+ls -A /sys/fs/cgroup
+touch /sys/fs/cgroup/x 2>/dev/null && echo cgroup-writable
+echo done`
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", script}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	<-job.Done()
+
+	stdout, stderr, err := manager.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() error = %v", err)
+	}
+	mask := fmt.Sprintf("%016x\n", kept)
+	if want := mask + mask + mask + "done\n"; string(stdout) != want {
+		t.Fatalf("stdout = %q, want %q (stderr %q)", stdout, want, stderr)
+	}
+}
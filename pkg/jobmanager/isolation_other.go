@@ -0,0 +1,22 @@
+//go:build !linux
+
+package jobmanager
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// isolatedCommand returns a command that fails to start, since namespaces
+// need Linux.
+func isolatedCommand(config initConfig, command string, args []string) *exec.Cmd {
+	cmd := exec.Command(command, args...)
+	cmd.Err = errors.New("job isolation requires Linux namespaces")
+	return cmd
+}
+
+// startIsolated starts cmd.
+func startIsolated(cmd *exec.Cmd, prepare func(pid int) error) (*os.File, error) {
+	return nil, cmd.Start()
+}
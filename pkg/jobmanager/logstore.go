@@ -0,0 +1,247 @@
+package jobmanager
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+const (
+	stdoutLogName = "stdout.log"
+	stderrLogName = "stderr.log"
+	indexName     = "index"
+
+	stdoutPipeName = "stdout.pipe"
+	stderrPipeName = "stderr.pipe"
+
+	// indexRecordSize is the size of one index record: timestamp (8),
+	// offset (8), length (4) and stream (1).
+	indexRecordSize = 21
+)
+
+// LogStore persists job output under a data directory so that it outlives
+// the server process. Each job gets its own directory holding one file per
+// stream and an index of the chunks appended to them:
+//
+//	<dir>/<job_id>/stdout.log
+//	<dir>/<job_id>/stderr.log
+//	<dir>/<job_id>/index
+//	<dir>/<job_id>/stdout.pipe
+//	<dir>/<job_id>/stderr.pipe
+//
+// A chunk is only considered written once its index record is, so output
+// torn by a crash is ignored when the logs are read back. The named pipes
+// carry the job's output to the server, and outlive it.
+type LogStore struct {
+	dir string
+}
+
+// NewLogStore creates a log store rooted at dir.
+func NewLogStore(dir string) (*LogStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory %s: %v", dir, err)
+	}
+	return &LogStore{dir: dir}, nil
+}
+
+// jobLog appends the output of one job to the store.
+type jobLog struct {
+	dir    string
+	stdout *os.File
+	stderr *os.File
+	index  *os.File
+	sizes  [2]int64
+}
+
+func (s *LogStore) jobDir(jobID string) string {
+	return filepath.Join(s.dir, jobID)
+}
+
+// Create prepares the log files of a new job.
+func (s *LogStore) Create(jobID string) (*jobLog, error) {
+	dir := s.jobDir(jobID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create job log directory %s: %v", dir, err)
+	}
+
+	l := &jobLog{dir: dir}
+	var err error
+	open := func(name string) *os.File {
+		if err != nil {
+			return nil
+		}
+		var f *os.File
+		f, err = os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		return f
+	}
+	l.stdout = open(stdoutLogName)
+	l.stderr = open(stderrLogName)
+	l.index = open(indexName)
+	if err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to open job log: %v", err)
+	}
+	return l, nil
+}
+
+// Reopen opens the log files of a job of a previous server run to append
+// to them. Output and index records torn by a crash are cut off first, so
+// new chunks follow the last committed one.
+func (s *LogStore) Reopen(jobID string) (*jobLog, error) {
+	dir := s.jobDir(jobID)
+	indexPath := filepath.Join(dir, indexName)
+	sizes, err := readIndexSizes(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(indexPath); err == nil {
+		if err := os.Truncate(indexPath, info.Size()-info.Size()%indexRecordSize); err != nil {
+			return nil, fmt.Errorf("failed to truncate log index: %v", err)
+		}
+	}
+	for stream, name := range []string{stdoutLogName, stderrLogName} {
+		if err := os.Truncate(filepath.Join(dir, name), sizes[stream]); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to truncate job log: %v", err)
+		}
+	}
+
+	l, err := s.Create(jobID)
+	if err != nil {
+		return nil, err
+	}
+	l.sizes = sizes
+	return l, nil
+}
+
+// createPipe creates the named pipe a job writes one stream of its output
+// to and returns its read end and the write end for the job. The write end
+// is opened for reading too, so the pipe keeps a reader while the job runs:
+// when the server goes away, the job's writes do not fail with EPIPE but
+// fill the pipe, and only block once it is full, until the next server
+// reopens it with openPipe.
+func (l *jobLog) createPipe(isStderr bool) (reader, writer *os.File, err error) {
+	path := l.pipePath(isStderr)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	if writer, err = os.OpenFile(path, os.O_RDWR, 0); err != nil {
+		return nil, nil, err
+	}
+	if reader, err = l.openPipe(isStderr); err != nil {
+		writer.Close()
+		return nil, nil, err
+	}
+	return reader, writer, nil
+}
+
+// openPipe opens the read end of a named pipe of the job. It does not wait
+// for a writer, so a pipe without one reads as empty.
+func (l *jobLog) openPipe(isStderr bool) (*os.File, error) {
+	return os.OpenFile(l.pipePath(isStderr), os.O_RDONLY|syscall.O_NONBLOCK, 0)
+}
+
+func (l *jobLog) pipePath(isStderr bool) string {
+	if isStderr {
+		return filepath.Join(l.dir, stderrPipeName)
+	}
+	return filepath.Join(l.dir, stdoutPipeName)
+}
+
+// Write appends a chunk of output and records it in the index.
+func (l *jobLog) Write(data []byte, isStderr bool) error {
+	stream, f := 0, l.stdout
+	if isStderr {
+		stream, f = 1, l.stderr
+	}
+
+	offset := l.sizes[stream]
+	n, err := f.Write(data)
+	l.sizes[stream] += int64(n)
+	if err != nil {
+		return err
+	}
+
+	var record [indexRecordSize]byte
+	binary.LittleEndian.PutUint64(record[0:], uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint64(record[8:], uint64(offset))
+	binary.LittleEndian.PutUint32(record[16:], uint32(n))
+	record[20] = byte(stream)
+	_, err = l.index.Write(record[:])
+	return err
+}
+
+// Close closes the log files.
+func (l *jobLog) Close() error {
+	var errs []error
+	for _, f := range []*os.File{l.stdout, l.stderr, l.index} {
+		if f != nil {
+			errs = append(errs, f.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OpenOutput returns readers of the indexed stdout and stderr of a stored
+// job.
+func (s *LogStore) OpenOutput(jobID string) (stdout, stderr *OutputReader, err error) {
+	dir := s.jobDir(jobID)
+	sizes, err := readIndexSizes(filepath.Join(dir, indexName))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if stdout, err = openPrefix(filepath.Join(dir, stdoutLogName), sizes[0]); err != nil {
+		return nil, nil, err
+	}
+	if stderr, err = openPrefix(filepath.Join(dir, stderrLogName), sizes[1]); err != nil {
+		stdout.Close()
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// Remove deletes everything stored for a job.
+func (s *LogStore) Remove(jobID string) error {
+	return os.RemoveAll(s.jobDir(jobID))
+}
+
+// readIndexSizes returns the committed size of each stream.
+func readIndexSizes(path string) ([2]int64, error) {
+	var sizes [2]int64
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sizes, nil
+	}
+	if err != nil {
+		return sizes, fmt.Errorf("failed to read log index: %v", err)
+	}
+
+	for len(data) >= indexRecordSize {
+		offset := int64(binary.LittleEndian.Uint64(data[8:]))
+		length := int64(binary.LittleEndian.Uint32(data[16:]))
+		if stream := data[20]; stream < 2 {
+			sizes[stream] = max(sizes[stream], offset+length)
+		}
+		data = data[indexRecordSize:]
+	}
+	return sizes, nil
+}
+
+// openPrefix returns a reader of the first size bytes of a log file.
+func openPrefix(path string, size int64) (*OutputReader, error) {
+	if size == 0 {
+		return &OutputReader{}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job log: %v", err)
+	}
+	return &OutputReader{file: f, fileSize: size}, nil
+}
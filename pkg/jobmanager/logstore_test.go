@@ -0,0 +1,120 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readStoredOutput(t *testing.T, store *LogStore, jobID string) (stdout, stderr string) {
+	t.Helper()
+	stdoutReader, stderrReader, err := store.OpenOutput(jobID)
+	if err != nil {
+		t.Fatalf("OpenOutput() error = %v", err)
+	}
+	defer stdoutReader.Close()
+	defer stderrReader.Close()
+	return readAll(t, stdoutReader), readAll(t, stderrReader)
+}
+
+func TestLogStoreRoundTrip(t *testing.T) {
+	store, err := NewLogStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLogStore() error = %v", err)
+	}
+
+	l, err := store.Create("job")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, chunk := range []struct {
+		data     string
+		isStderr bool
+	}{{"hello ", false}, {"oops", true}, {"world", false}} {
+		if err := l.Write([]byte(chunk.data), chunk.isStderr); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	stdout, stderr := readStoredOutput(t, store, "job")
+	if stdout != "hello world" || stderr != "oops" {
+		t.Fatalf("OpenOutput() = (%q, %q), want (%q, %q)", stdout, stderr, "hello world", "oops")
+	}
+}
+
+func TestLogStoreIgnoresUnindexedOutput(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	if err != nil {
+		t.Fatalf("NewLogStore() error = %v", err)
+	}
+
+	l, err := store.Create("job")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := l.Write([]byte("committed\n"), false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	l.Close()
+
+	// Simulate a crash between the data write and its index record
+	f, err := os.OpenFile(filepath.Join(dir, "job", stdoutLogName), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("torn")
+	f.Close()
+
+	stdout, _ := readStoredOutput(t, store, "job")
+	if stdout != "committed\n" {
+		t.Fatalf("OpenOutput() stdout = %q, want %q", stdout, "committed\n")
+	}
+}
+
+func TestLogStoreReopenAppendsAfterCommittedOutput(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	if err != nil {
+		t.Fatalf("NewLogStore() error = %v", err)
+	}
+
+	l, err := store.Create("job")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := l.Write([]byte("before\n"), false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	l.Close()
+
+	// Simulate a crash that tore both a chunk and an index record
+	for name, torn := range map[string]string{stdoutLogName: "torn", indexName: "partial"} {
+		f, err := os.OpenFile(filepath.Join(dir, "job", name), os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(torn)
+		f.Close()
+	}
+
+	l, err = store.Reopen("job")
+	if err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	if err := l.Write([]byte("after\n"), false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := l.Write([]byte("oops"), true); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	l.Close()
+
+	stdout, stderr := readStoredOutput(t, store, "job")
+	if stdout != "before\nafter\n" || stderr != "oops" {
+		t.Fatalf("OpenOutput() = (%q, %q), want (%q, %q)", stdout, stderr, "before\nafter\n", "oops")
+	}
+}
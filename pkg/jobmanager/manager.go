@@ -3,16 +3,21 @@ package jobmanager
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"github.com/arazmj/sentry-run/pkg/limits"
 	"github.com/google/uuid"
 	"io"
 	"log/slog"
+	"net/netip"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
 var logger = slog.Default()
@@ -27,102 +32,284 @@ func SetLogger(l *slog.Logger) {
 type Job struct {
 	ID            string
 	PID           int
+	Owner         string
 	Command       string
+	Args          []string
 	Cmd           *exec.Cmd
 	Stdout        io.ReadCloser
 	Stderr        io.ReadCloser
-	stdoutHistory bytes.Buffer
-	stderrHistory bytes.Buffer
-	callbacks     []OutputCallback
+	stdoutHistory *outputHistory
+	stderrHistory *outputHistory
+	subscribers   map[int]*subscriber
+	nextSub       int
 	mu            sync.Mutex
-	MemoryLimit   string
-	CpuLimit      string
-	Mount         string
-	WriteBps      string
-	ReadBps       string
-	DeviceId      string
+	status        Status
+	waitErr       error
+	done          chan struct{}
+	log           *jobLog
+	// lastSignal is the last signal delivered to the job by the manager
+	lastSignal syscall.Signal
+	// recovered is set for jobs of previous server runs, which have no Cmd
+	// or output pipes
+	recovered bool
+	// Limits and DeviceId change when the job's limits are updated; read
+	// them through ResourceLimits and Devices while the job runs
+	Limits limits.Limits
+	Mount  string
+	// Isolated jobs run in their own PID, mount, UTS and IPC namespaces
+	Isolated bool
+	Network  NetworkMode
+	// initStatus is the pipe on which the init of an isolated job reports
+	// how the job command ended
+	initStatus *os.File
+	// Image is the image the job's root is an overlay of, and LayerDir the
+	// directory of its upper and work directories
+	Image      string
+	LayerDir   string
+	KeepRootfs bool
+	// Address is the address of a bridged job, with the subnet's prefix
+	// length
+	Address string
+	// veth connects a bridged job to bridge while it runs
+	veth   *vethPair
+	bridge *bridgeNetwork
+	// DeviceId lists the "major:minor" of the disks the IO limits apply to
+	DeviceId string
+	// diskIO are the IO limits resolved to whole disks when the job was
+	// started
+	diskIO []limits.IOLimit
+	// limitChanges is the history of limit updates and updateMu
+	// serializes them
+	limitChanges []LimitChange
+	updateMu     sync.Mutex
+	// saveMu serializes saveRecord, so records land in the order their
+	// snapshots were taken
+	saveMu sync.Mutex
 }
 
 type JobManager struct {
-	jobs sync.Map
+	jobs            sync.Map
+	stopGracePeriod time.Duration
+	historyLimit    int
+	spillDir        string
+	logStore        *LogStore
+	registry        *Registry
+
+	defaultPidsLimit uint64
+	pressureTrigger  *PressureTrigger
+	isolate          bool
+	rootfs           RootfsConfig
+	protected        []string
+	bridgeName       string
+	bridgeSubnet     netip.Prefix
+	bridgeDNS        []netip.Addr
+	bridge           *bridgeNetwork
+	bridgeErr        error
+
+	cgroupGCInterval time.Duration
+	cgroupGCDryRun   bool
+	gcMu             sync.Mutex
+	lastGCReport     *CgroupGCReport
+
+	// closed stops the goroutines watching jobs, which watchers tracks
+	closed    chan struct{}
+	closeOnce sync.Once
+	watchers  sync.WaitGroup
 }
 
 // OutputCallback is called for each line of output from the job
 type OutputCallback func(data []byte, isStderr bool) error
 
 // New creates a new JobManager
-func New() *JobManager {
-	return &JobManager{}
+func New(opts ...Option) *JobManager {
+	m := &JobManager{
+		stopGracePeriod:  DefaultStopGracePeriod,
+		historyLimit:     DefaultHistoryLimit,
+		cgroupGCInterval: DefaultCgroupGCInterval,
+		bridgeName:       DefaultBridgeName,
+		bridgeSubnet:     DefaultBridgeSubnet,
+		rootfs:           RootfsConfig{Binds: DefaultRootfsBinds, Layers: DefaultRootfsLayers},
+		closed:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.bridgeDNS == nil {
+		m.bridgeDNS = hostResolvers()
+	}
+	if m.bridge, m.bridgeErr = newBridgeNetwork(m.bridgeName, m.bridgeSubnet, m.bridgeDNS); m.bridgeErr != nil {
+		logger.Warn("invalid bridge configuration, bridged jobs cannot start", "error", m.bridgeErr)
+	}
+	return m
+}
+
+// Close stops the goroutines watching the memory events and pressure of
+// jobs and the exit of re-adopted jobs, and waits for them to return. Jobs
+// keep running, but re-adopted jobs are no longer noticed finishing. No job
+// may be started or recovered after Close.
+func (m *JobManager) Close() {
+	m.closeOnce.Do(func() { close(m.closed) })
+	m.watchers.Wait()
+}
+
+// goWatch runs watch in a goroutine that Close waits for.
+func (m *JobManager) goWatch(watch func()) {
+	m.watchers.Add(1)
+	go func() {
+		defer m.watchers.Done()
+		watch()
+	}()
+}
+
+// watchCgroup watches the memory events and, when a pressure trigger is
+// configured, the pressure of a job's cgroup until the job finishes or the
+// manager is closed. The cgroup path is resolved before the watchers start.
+func (m *JobManager) watchCgroup(job *Job) {
+	cgroupPath := getCgroupPath(job.ID)
+	stop := make(chan struct{})
+	m.goWatch(func() {
+		select {
+		case <-job.done:
+		case <-m.closed:
+		}
+		close(stop)
+	})
+	m.goWatch(func() { watchMemoryEvents(job, cgroupPath, stop) })
+	if m.pressureTrigger != nil {
+		m.watchPressure(job, cgroupPath, *m.pressureTrigger, stop)
+	}
 }
 
+// cgroupBasePath is the root of the cgroup v2 hierarchy. It is a variable
+// so that tests can point it at a scratch directory.
+var cgroupBasePath = "/sys/fs/cgroup"
+
 const (
-	cgroupBasePath = "/sys/fs/cgroup"
-	cgroupName     = "sentry-run"
+	cgroupName = "sentry-run"
+
+	// outputDrainTimeout bounds how long output is drained after the job
+	// process exits, in case descendants still hold the pipes open.
+	outputDrainTimeout = 2 * time.Second
 )
 
 func getCgroupPath(jobID string) string {
 	return filepath.Join(cgroupBasePath, fmt.Sprintf("%s-%s", cgroupName, jobID))
 }
 
-func setLimits(pid int, jobID, cpuLimit, memoryLimit, writeBps, readBps string) error {
+// createCgroup creates the job's cgroup with its limits applied and returns
+// the open cgroup directory, so the job process can be cloned straight into
+// it before it executes any code.
+func createCgroup(jobID string, jobLimits limits.Limits) (*os.File, error) {
 	cgroupPath := getCgroupPath(jobID)
 
 	if err := os.MkdirAll(cgroupPath, 0755); err != nil {
-		return fmt.Errorf("failed to create cgroup directory %s: %v", cgroupPath, err)
+		return nil, fmt.Errorf("failed to create cgroup directory %s: %v", cgroupPath, err)
 	}
 
-	// Associate process with cgroup
-	procsPath := filepath.Join(cgroupPath, "cgroup.procs")
-	if err := os.WriteFile(procsPath, []byte(strconv.Itoa(pid)), 0644); err != nil {
-		return fmt.Errorf("failed to add process to CPU cgroup: %v", err)
+	if err := setLimits(cgroupPath, jobLimits); err != nil {
+		return nil, err
+	}
 
+	dir, err := os.Open(cgroupPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cgroup directory %s: %v", cgroupPath, err)
 	}
+	return dir, nil
+}
 
-	if cpuLimit != "" {
-		cpuPath := filepath.Join(cgroupPath, "cpu.max")
-		if err := os.WriteFile(cpuPath, []byte(cpuLimit), 0644); err != nil {
-			return fmt.Errorf("failed to set CPU cpu limit: %v", err)
-		}
+// setLimits writes the limits that are set to the cgroup interface files.
+// The devices of IO limits must already be resolved to whole disks.
+func setLimits(cgroupPath string, jobLimits limits.Limits) error {
+	var files []struct{ name, value string }
+	add := func(name, value string) {
+		files = append(files, struct{ name, value string }{name, value})
 	}
 
-	if memoryLimit != "" {
-		memoryPath := filepath.Join(cgroupPath, "memory.max")
-		if err := os.WriteFile(memoryPath, []byte(memoryLimit), 0644); err != nil {
-			return fmt.Errorf("failed to set memory memoryLimit: %v", err)
-		}
+	if jobLimits.MemoryMax != 0 {
+		add("memory.max", limits.FormatValue(jobLimits.MemoryMax))
+	}
+	if jobLimits.MemoryHigh != 0 {
+		add("memory.high", limits.FormatValue(jobLimits.MemoryHigh))
+	}
+	if jobLimits.MemorySwapMax != nil {
+		add("memory.swap.max", limits.FormatValue(*jobLimits.MemorySwapMax))
+	}
+	if cpuMax := jobLimits.CPUMax(); cpuMax != "" {
+		add("cpu.max", cpuMax)
+	}
+	if jobLimits.CPUWeight != 0 {
+		add("cpu.weight", limits.FormatValue(jobLimits.CPUWeight))
+	}
+	if jobLimits.PidsMax != 0 {
+		add("pids.max", limits.FormatValue(jobLimits.PidsMax))
+	}
+	for _, io := range jobLimits.IO {
+		add("io.max", io.Device+" "+io.IOMax())
 	}
 
-	if writeBps != "" || readBps != "" {
-		// Get file system stats for the current working directory
-		var stat syscall.Stat_t
-		if err := syscall.Stat("/", &stat); err != nil { // Root filesystem
-			return fmt.Errorf("failed to stat filesystem: %v", err)
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(cgroupPath, file.name), []byte(file.value), 0644); err != nil {
+			return fmt.Errorf("failed to set %s to %q: %v", file.name, file.value, err)
 		}
+	}
+	return nil
+}
+
+// signalJob delivers sig to every process of the job: the process group
+// created with Setpgid and, when the job has a cgroup, every member of it.
+// SIGKILL uses cgroup.kill where the kernel supports it. Jobs without a
+// process yet are refused: kill(-0) would signal the server's own group.
+func signalJob(job *Job, sig syscall.Signal) error {
+	job.mu.Lock()
+	pid := job.PID
+	if pid > 0 {
+		job.lastSignal = sig
+	}
+	job.mu.Unlock()
+	if pid <= 0 {
+		return fmt.Errorf("job %s has no process", job.ID)
+	}
 
-		// Extract major and minor device numbers
-		major := (stat.Dev >> 8) & 0xfff // Major number
-		minor := 0                       // Minor should be set to zero because cgroups only works on whole device block
+	err := syscall.Kill(-pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		err = nil
+	}
 
-		ioLimit := fmt.Sprintf("%d:%d", major, minor)
+	cgroupPath := getCgroupPath(job.ID)
+	if _, statErr := os.Stat(cgroupPath); statErr != nil {
+		return err
+	}
 
-		ioPath := filepath.Join(cgroupPath, "io.max")
-		if writeBps != "" {
-			ioLimit += fmt.Sprintf(" wbps=%s", writeBps)
-		}
-		if readBps != "" {
-			ioLimit += fmt.Sprintf(" rbps=%s", readBps)
+	if sig == syscall.SIGKILL {
+		killErr := os.WriteFile(filepath.Join(cgroupPath, "cgroup.kill"), []byte("1"), 0644)
+		if killErr == nil {
+			return nil
 		}
+		logger.Debug("cgroup.kill unavailable, signalling members", "job_id", job.ID, "error", killErr)
+	}
 
-		if err := os.WriteFile(ioPath, []byte(ioLimit), 0644); err != nil {
-			return fmt.Errorf("failed to set io limit: %v", err)
+	data, readErr := os.ReadFile(filepath.Join(cgroupPath, "cgroup.procs"))
+	if readErr != nil {
+		return err
+	}
+	for _, field := range bytes.Fields(data) {
+		pid, convErr := strconv.Atoi(string(field))
+		if convErr != nil {
+			continue
+		}
+		if killErr := syscall.Kill(pid, sig); killErr != nil && !errors.Is(killErr, syscall.ESRCH) {
+			err = killErr
 		}
 	}
-	return nil
+	return err
 }
 
 func cleanupCgroup(job *Job) error {
-	cgroupPath := getCgroupPath(job.ID)
+	return deleteCgroupDir(getCgroupPath(job.ID))
+}
 
+// deleteCgroupDir removes the cgroup directory at cgroupPath, if it exists.
+func deleteCgroupDir(cgroupPath string) error {
 	// The cgroup dir can only be deleted by rmdir syscall
 	if err := syscall.Rmdir(cgroupPath); err != nil {
 		if os.IsNotExist(err) {
@@ -134,201 +321,713 @@ func cleanupCgroup(job *Job) error {
 	return nil
 }
 
-// StartJob starts a new job and returns its ID
-func (m *JobManager) StartJob(command string, commandArgs []string, memoryLimit, cpuLimit, mount, writeBps, readBps string) (*Job, error) {
-	cmd := exec.Command(command, commandArgs...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+// StartOptions are the settings of a job besides its command and limits.
+type StartOptions struct {
+	// Mount is a host directory bind-mounted into the job's root filesystem,
+	// in which the job starts. It requires isolation.
+	Mount string
+	// Network selects the job's network namespace. Modes other than
+	// NetworkHost require isolation.
+	Network NetworkMode
+	// Image is the image the job's root filesystem is an overlay of. The
+	// job's changes are discarded when it is removed unless KeepRootfs is
+	// set. It requires isolation.
+	Image      string
+	KeepRootfs bool
+}
 
-	if mount != "" {
-		//cmd.SysProcAttr.Cloneflags = syscall.CLONE_NEWNS
-		cmd.SysProcAttr.Chroot = mount
-	}
+// InvalidOptionsError reports start options that StartJob rejects before
+// creating the job.
+type InvalidOptionsError struct {
+	Err error
+}
+
+func (e *InvalidOptionsError) Error() string { return e.Err.Error() }
+func (e *InvalidOptionsError) Unwrap() error { return e.Err }
 
-	stdout, err := cmd.StdoutPipe()
+// validateStartOptions checks opts against the manager's configuration,
+// resolves the mount directory in place and resolves the devices of the IO
+// limits. It returns the directory of the job's image, if it has one, and
+// the IO limits of its disks.
+func (m *JobManager) validateStartOptions(opts *StartOptions, ios []limits.IOLimit) (string, []limits.IOLimit, error) {
+	if _, err := ParseNetworkMode(string(opts.Network)); err != nil {
+		return "", nil, err
+	}
+	if opts.Network != NetworkHost && !m.isolate {
+		return "", nil, fmt.Errorf("network mode %s requires job isolation", opts.Network)
+	}
+	if opts.Mount != "" {
+		if !m.isolate {
+			return "", nil, fmt.Errorf("mounting a directory requires job isolation")
+		}
+		mount, err := m.rootfs.mountPath(opts.Mount, m.protectedPaths())
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid mount directory: %v", err)
+		}
+		opts.Mount = mount
+	}
+	diskIO, err := resolveIO(ios)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %v", err)
+		return "", nil, fmt.Errorf("invalid IO limits: %v", err)
 	}
-
-	stderr, err := cmd.StderrPipe()
+	if opts.Image == "" {
+		return "", diskIO, nil
+	}
+	if !m.isolate {
+		return "", nil, fmt.Errorf("starting a job from an image requires job isolation")
+	}
+	image, err := m.rootfs.imagePath(opts.Image)
 	if err != nil {
-		stdout.Close()
-		return nil, fmt.Errorf("failed to create stderr pipe: %v", err)
+		return "", nil, err
 	}
+	return image, diskIO, nil
+}
 
-	if err := cmd.Start(); err != nil {
-		stdout.Close()
-		stderr.Close()
-		return nil, fmt.Errorf("failed to start command: %v", err)
+// privatePaths returns the host directories holding the server's state and
+// the output and files of jobs: the log store, registry and spill files, the
+// layers of image jobs and the paths protected by WithProtectedPaths.
+func (m *JobManager) privatePaths() []string {
+	paths := []string{m.rootfs.Layers}
+	if m.spillDir != "" {
+		paths = append(paths, m.spillDir)
+	}
+	if m.logStore != nil {
+		paths = append(paths, m.logStore.dir)
+	}
+	if m.registry != nil {
+		paths = append(paths, m.registry.dir)
+	}
+	return append(paths, m.protected...)
+}
+
+// protectedPaths returns the host paths no job may mount: the private
+// paths and the sources of job root filesystems.
+func (m *JobManager) protectedPaths() []string {
+	paths := []string{DefaultRootfsStaging, bridgeRuntimeDir}
+	paths = append(paths, m.rootfs.Binds...)
+	for _, path := range []string{m.rootfs.Base, m.rootfs.Images} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return append(paths, m.privatePaths()...)
+}
+
+// StartJob starts a new job on behalf of owner and returns its ID.
+// A job that cannot be started is kept in the failed-to-start state.
+// Invalid options are reported as *InvalidOptionsError.
+func (m *JobManager) StartJob(owner, command string, commandArgs []string, jobLimits limits.Limits, opts StartOptions) (*Job, error) {
+	if opts.Network == "" {
+		opts.Network = NetworkHost
+	}
+	image, diskIO, err := m.validateStartOptions(&opts, jobLimits.IO)
+	if err != nil {
+		return nil, &InvalidOptionsError{Err: err}
 	}
 
 	jobUUID, err := uuid.NewUUID()
 	if err != nil {
-		stdout.Close()
-		stderr.Close()
 		return nil, fmt.Errorf("failed to generate job uuid: %v", err)
 	}
-	jobID := jobUUID.String()
 
-	if memoryLimit != "" || cpuLimit != "" || writeBps != "" || readBps != "" {
-		if err := setLimits(cmd.Process.Pid, jobID, cpuLimit, memoryLimit, writeBps, readBps); err != nil {
-			if killErr := cmd.Process.Kill(); killErr != nil {
-				return nil, killErr
+	if jobLimits.PidsMax == 0 {
+		jobLimits.PidsMax = m.defaultPidsLimit
+	}
+
+	var veth *vethPair
+	if opts.Network == NetworkBridged {
+		if m.bridgeErr != nil {
+			return nil, m.bridgeErr
+		}
+		if veth, err = m.bridge.attach(jobUUID.String()); err != nil {
+			return nil, fmt.Errorf("failed to connect job to bridge: %v", err)
+		}
+	}
+
+	var layerDir string
+	if image != "" {
+		if layerDir, err = m.rootfs.createLayer(jobUUID.String()); err != nil {
+			if veth != nil {
+				m.bridge.detach(veth)
 			}
-			return nil, fmt.Errorf("failed to set limits: %v", err)
+			return nil, err
 		}
 	}
 
-	job := &Job{
-		ID:          jobID,
-		PID:         cmd.Process.Pid,
-		Command:     command,
-		Cmd:         cmd,
-		Stdout:      stdout,
-		Stderr:      stderr,
-		callbacks:   []OutputCallback{},
-		MemoryLimit: memoryLimit,
-		CpuLimit:    cpuLimit,
-		Mount:       mount,
-		WriteBps:    writeBps,
-		ReadBps:     readBps,
-	}
-
-	go job.serveSubscribers()
+	var cmd *exec.Cmd
+	if m.isolate {
+		config := initConfig{Hostname: jobUUID.String(), Network: initNetwork{Mode: opts.Network}}
+		switch {
+		case image != "":
+			// Images bring their own toolchain, host paths would shadow it
+			config.Rootfs = &initRootfs{
+				Staging: DefaultRootfsStaging,
+				Image:   image,
+				Upper:   filepath.Join(layerDir, "upper"),
+				Work:    filepath.Join(layerDir, "work"),
+				Mount:   opts.Mount,
+			}
+		case opts.Mount != "" || m.rootfs.Base != "":
+			config.Rootfs = &initRootfs{
+				Staging: DefaultRootfsStaging,
+				Base:    m.rootfs.Base,
+				Binds:   m.rootfs.Binds,
+				Mount:   opts.Mount,
+			}
+		default:
+			config.Hidden = m.privatePaths()
+		}
+		if veth != nil {
+			config.Network.Link = veth.Peer
+			config.Network.Address = veth.Address.String()
+			config.Network.Gateway = m.bridge.gateway.String()
+			config.Network.ResolvConf = m.bridge.resolvConf
+		}
+		cmd = isolatedCommand(config, command, commandArgs)
+	} else {
+		cmd = exec.Command(command, commandArgs...)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
+
+	var address string
+	if veth != nil {
+		address = veth.Address.String()
+	}
 
+	job := &Job{
+		ID:            jobUUID.String(),
+		Owner:         owner,
+		Command:       command,
+		Args:          commandArgs,
+		Cmd:           cmd,
+		subscribers:   make(map[int]*subscriber),
+		stdoutHistory: newOutputHistory(m.historyLimit, m.spillPath(jobUUID.String(), "stdout")),
+		stderrHistory: newOutputHistory(m.historyLimit, m.spillPath(jobUUID.String(), "stderr")),
+		Limits:        jobLimits,
+		Mount:         opts.Mount,
+		Isolated:      m.isolate,
+		Network:       opts.Network,
+		Image:         opts.Image,
+		LayerDir:      layerDir,
+		KeepRootfs:    opts.KeepRootfs,
+		DeviceId:      deviceIDs(diskIO),
+		Address:       address,
+		diskIO:        diskIO,
+		veth:          veth,
+		bridge:        m.bridge,
+		status:        Status{State: StatePending},
+		done:          make(chan struct{}),
+	}
 	m.jobs.Store(job.ID, job)
+
+	if m.logStore != nil {
+		if job.log, err = m.logStore.Create(job.ID); err != nil {
+			logger.Warn("failed to create job log, output will not be persisted", "job_id", job.ID, "error", err)
+		}
+	}
+
+	if err := job.start(); err != nil {
+		job.detachNetwork()
+		job.markFailed(err)
+		m.saveRecord(job)
+		m.closeLog(job)
+		return nil, err
+	}
+	m.saveRecord(job)
+
+	if !job.Limits.IsZero() {
+		m.watchCgroup(job)
+	}
+	go func() {
+		job.run()
+		m.saveRecord(job)
+		m.closeLog(job)
+	}()
+
 	return job, nil
 }
 
-// StopJob stops a running job.
-// Closing stdout/stderr here may race with serveSubscribers reads; fixing that
-// requires a broader lifecycle refactor and is intentionally out of scope.
-func (m *JobManager) StopJob(jobID string) error {
-	value, exists := m.jobs.LoadAndDelete(jobID)
-	if !exists {
-		return fmt.Errorf("job %s not found", jobID)
+// detachNetwork disconnects a bridged job from the bridge once it no longer
+// runs.
+func (job *Job) detachNetwork() {
+	job.mu.Lock()
+	veth := job.veth
+	job.veth = nil
+	job.mu.Unlock()
+	if veth != nil {
+		job.bridge.detach(veth)
 	}
+}
 
-	job := value.(*Job)
-	if err := job.Cmd.Process.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("failed to stop job %s: %v", jobID, err)
+// saveRecord persists the job's current metadata and status in the registry.
+func (m *JobManager) saveRecord(job *Job) {
+	if m.registry == nil {
+		return
+	}
+	job.saveMu.Lock()
+	defer job.saveMu.Unlock()
+	job.mu.Lock()
+	record := jobRecord{
+		ID:           job.ID,
+		Owner:        job.Owner,
+		Command:      job.Command,
+		Args:         job.Args,
+		Limits:       job.Limits,
+		Mount:        job.Mount,
+		Isolated:     job.Isolated,
+		Network:      job.Network,
+		Address:      job.Address,
+		Image:        job.Image,
+		LayerDir:     job.LayerDir,
+		KeepRootfs:   job.KeepRootfs,
+		DeviceId:     job.DeviceId,
+		PID:          job.PID,
+		LimitChanges: job.limitChanges,
+		CgroupPath:   getCgroupPath(job.ID),
+		Status:       job.status,
+	}
+	job.mu.Unlock()
+	if err := m.registry.Save(record); err != nil {
+		logger.Warn("failed to persist job record", "job_id", job.ID, "error", err)
 	}
+}
 
-	job.Stdout.Close()
-	job.Stderr.Close()
+// closeLog closes the job's log files once no more output will be written.
+func (m *JobManager) closeLog(job *Job) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.log == nil {
+		return
+	}
+	if err := job.log.Close(); err != nil {
+		logger.Warn("failed to close job log", "job_id", job.ID, "error", err)
+	}
+	job.log = nil
+}
+
+// spillPath returns the file a job stream spills evicted output to, or ""
+// when spilling is disabled.
+func (m *JobManager) spillPath(jobID, stream string) string {
+	if m.spillDir == "" {
+		return ""
+	}
+	return filepath.Join(m.spillDir, fmt.Sprintf("%s.%s.log", jobID, stream))
+}
 
-	// Clean up cgroups
+// removeCgroup removes the cgroup of a job that failed to start.
+func (job *Job) removeCgroup() {
 	if err := cleanupCgroup(job); err != nil {
-		logger.Warn("failed to clean up cgroups", "job_id", jobID, "pid", job.PID, "error", err)
+		logger.Warn("failed to clean up cgroups", "job_id", job.ID, "error", err)
+	}
+}
+
+// outputPipe returns the read end of one output stream of the job and the
+// write end for its process. Jobs with a log get named pipes in their log
+// directory, which a restarted server can reopen, and others plain pipes.
+func (job *Job) outputPipe(isStderr bool) (*os.File, *os.File, error) {
+	if job.log != nil {
+		return job.log.createPipe(isStderr)
+	}
+	return os.Pipe()
+}
+
+// start creates the job cgroup and launches the job process inside it, so
+// the limits apply from its first instruction.
+// The job owns the read ends of the output pipes so that reaping the
+// process does not close them while output is still being drained.
+func (job *Job) start() error {
+	cmd := job.Cmd
+
+	if !job.Limits.IsZero() {
+		cgroupLimits := job.Limits
+		cgroupLimits.IO = job.diskIO
+
+		cgroup, err := createCgroup(job.ID, cgroupLimits)
+		if err != nil {
+			job.removeCgroup()
+			return fmt.Errorf("failed to set limits: %v", err)
+		}
+		defer cgroup.Close()
+
+		if err := useCgroup(cmd.SysProcAttr, cgroup); err != nil {
+			job.removeCgroup()
+			return err
+		}
+	}
+
+	stdout, stdoutWriter, err := job.outputPipe(false)
+	if err != nil {
+		job.removeCgroup()
+		return fmt.Errorf("failed to create stdout pipe: %v", err)
+	}
+
+	stderr, stderrWriter, err := job.outputPipe(true)
+	if err != nil {
+		stdout.Close()
+		stdoutWriter.Close()
+		job.removeCgroup()
+		return fmt.Errorf("failed to create stderr pipe: %v", err)
+	}
+
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	if job.Isolated {
+		var prepare func(pid int) error
+		if job.veth != nil {
+			prepare = func(pid int) error { return job.bridge.moveToJob(job.veth, pid) }
+		}
+		job.initStatus, err = startIsolated(cmd, prepare)
+	} else {
+		err = cmd.Start()
+	}
+
+	// The child holds its own copies of the write ends
+	stdoutWriter.Close()
+	stderrWriter.Close()
+
+	if err != nil {
+		stdout.Close()
+		stderr.Close()
+		job.removeCgroup()
+		return fmt.Errorf("failed to start command: %v", err)
 	}
 
+	// The job is already listed, so other goroutines may read these
+	job.mu.Lock()
+	job.PID = cmd.Process.Pid
+	job.Stdout = stdout
+	job.Stderr = stderr
+	job.mu.Unlock()
+	job.markRunning()
 	return nil
 }
 
-// GetJobStatus returns whether the job is running
-func (m *JobManager) GetJobStatus(jobID string) (bool, error) {
-	value, exists := m.jobs.Load(jobID)
-	if !exists {
-		return false, fmt.Errorf("job %s not found", jobID)
+// StopResult describes how StopJob ended a job.
+type StopResult struct {
+	// Escalated is true when the job outlived the grace period after
+	// SIGTERM and was ended with SIGKILL.
+	Escalated bool
+	// Status is the final status of the job.
+	Status Status
+}
+
+// StopJob sends SIGTERM to every process of a running job, waits up to
+// gracePeriod (or the manager default when zero) for it to exit and then
+// escalates to SIGKILL. The job's owner goroutine reaps the process and
+// removes its cgroup once it exits.
+func (m *JobManager) StopJob(jobID string, gracePeriod time.Duration) (StopResult, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return StopResult{}, err
+	}
+
+	// Pending jobs have no process to signal yet
+	if !job.Status().IsRunning() {
+		return StopResult{}, fmt.Errorf("job %s is not running", jobID)
+	}
+	if gracePeriod <= 0 {
+		gracePeriod = m.stopGracePeriod
+	}
+
+	if err := signalJob(job, syscall.SIGTERM); err != nil {
+		return StopResult{}, fmt.Errorf("failed to stop job %s: %v", jobID, err)
+	}
+
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-job.done:
+		return StopResult{Status: job.Status()}, nil
+	case <-timer.C:
 	}
 
-	job := value.(*Job)
-	if err := job.Cmd.Process.Signal(syscall.Signal(0)); err != nil {
-		return false, nil
+	logger.Info("job did not stop within grace period, killing", "job_id", jobID, "pid", job.PID, "grace_period", gracePeriod)
+	if err := signalJob(job, syscall.SIGKILL); err != nil {
+		return StopResult{Escalated: true}, fmt.Errorf("failed to kill job %s: %v", jobID, err)
 	}
+	<-job.done
 
-	return true, nil
+	return StopResult{Escalated: true, Status: job.Status()}, nil
 }
 
-func (m *JobManager) StreamOutput(ctx context.Context, jobID string, callback OutputCallback) error {
+// GetJob returns the job with the given ID
+func (m *JobManager) GetJob(jobID string) (*Job, error) {
 	value, exists := m.jobs.Load(jobID)
 	if !exists {
-		return fmt.Errorf("job %s not found", jobID)
+		return nil, fmt.Errorf("job %s not found", jobID)
 	}
+	return value.(*Job), nil
+}
 
-	job := value.(*Job)
+// GetJobPids returns the number of processes in the job's cgroup, or zero
+// once the job has finished.
+func (m *JobManager) GetJobPids(jobID string) (uint64, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return 0, err
+	}
+	if job.Status().State.Finished() {
+		return 0, nil
+	}
+	return readCgroupUint(jobID, "pids.current")
+}
 
-	// Add callback for future output
-	job.mu.Lock()
-	job.callbacks = append(job.callbacks, callback)
-	job.mu.Unlock()
+// readCgroupUint reads a single value cgroup interface file of a job.
+func readCgroupUint(jobID, name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(getCgroupPath(jobID), name))
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return limits.Max, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+	return n, nil
+}
 
-	doneCh := make(chan error, 1)
+// GetJobStatus returns the lifecycle status of the job
+func (m *JobManager) GetJobStatus(jobID string) (Status, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return Status{}, err
+	}
 
-	go func() {
-		doneCh <- job.Cmd.Wait()
-	}()
+	return job.Status(), nil
+}
+
+// WaitJob blocks until the job finishes or ctx is done and returns its final status
+func (m *JobManager) WaitJob(ctx context.Context, jobID string) (Status, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return Status{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return job.Status(), ctx.Err()
+	case <-job.done:
+		return job.Status(), nil
+	}
+}
+
+// StreamOutput registers callback for the job's future output and blocks
+// until the job finishes or ctx is canceled.
+func (m *JobManager) StreamOutput(ctx context.Context, jobID string, callback OutputCallback) error {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return err
+	}
+	// The output of jobs of previous server runs is only persisted
+	if job.recovered {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-job.done:
+			return nil
+		}
+	}
+
+	// Add callback for future output
+	id, sub := job.subscribe(callback)
+	defer job.unsubscribe(id)
 
 	select {
 	case <-ctx.Done():
 		// context canceled, return immediately
 		return ctx.Err()
-	case err := <-doneCh:
-		// process exited naturally
+	case err := <-sub.failed:
+		// subscriber went away
+		return err
+	case <-job.done:
+		// process exited
+		return job.exitError()
+	}
+}
+
+// subscriber delivers job output to one StreamOutput caller.
+type subscriber struct {
+	mu       sync.Mutex
+	callback OutputCallback
+	closed   bool
+	failed   chan error
+}
+
+// send delivers data unless the subscriber has been closed. Holding the lock
+// across the callback guarantees no delivery is in flight after close returns.
+func (s *subscriber) send(data []byte, isStderr bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	if err := s.callback(data, isStderr); err != nil {
+		s.closed = true
+		s.failed <- err
 		return err
 	}
+	return nil
+}
+
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+func (job *Job) subscribe(callback OutputCallback) (int, *subscriber) {
+	sub := &subscriber{callback: callback, failed: make(chan error, 1)}
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	id := job.nextSub
+	job.nextSub++
+	job.subscribers[id] = sub
+	return id, sub
+}
+
+func (job *Job) unsubscribe(id int) {
+	job.mu.Lock()
+	sub, ok := job.subscribers[id]
+	delete(job.subscribers, id)
+	job.mu.Unlock()
+	if ok {
+		sub.close()
+	}
 }
 
-func (job *Job) serveSubscribers() {
+// run is the single owner of the job process. It reaps the process exactly
+// once, records the result, and broadcasts completion by closing job.done
+// after the output has been drained.
+func (job *Job) run() {
 	var wg sync.WaitGroup
 	wg.Add(2)
+	go job.pumpOutput(&wg, job.Stdout, job.stdoutHistory, false)
+	go job.pumpOutput(&wg, job.Stderr, job.stderrHistory, true)
 
-	streamOutput := func(reader io.Reader, history *bytes.Buffer, isStderr bool) {
-		defer wg.Done()
-		buffer := make([]byte, 32*1024)
-		for {
-			n, err := reader.Read(buffer)
-			if n > 0 {
-				data := buffer[:n]
-				job.mu.Lock()
-				history.Write(data)
-				callbacks := append([]OutputCallback(nil), job.callbacks...)
-				job.mu.Unlock()
-				for _, callback := range callbacks {
-					err := callback(data, isStderr)
-					if err != nil {
-						logger.Warn("failed to send callback", "job_id", job.ID, "pid", job.PID, "error", err)
-						return
-					}
-				}
-			}
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				logger.Warn("failed to read job output", "job_id", job.ID, "pid", job.PID, "error", err)
-				break
-			}
-		}
-	}
+	job.markFinished(job.Cmd.Wait())
+	status := job.Status()
+	logger.Info("job finished", "job_id", job.ID, "pid", job.PID, "state", status.State, "exit_code", status.ExitCode)
 
-	go streamOutput(job.Stdout, &job.stdoutHistory, false)
-	go streamOutput(job.Stderr, &job.stderrHistory, true)
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
 
-	// Wait for both streams to complete in a separate goroutine
-	wg.Wait()
+	// Descendants that outlive the job may keep the pipes open
+	select {
+	case <-drained:
+	case <-time.After(outputDrainTimeout):
+		logger.Warn("job output still open after exit, closing pipes", "job_id", job.ID, "pid", job.PID)
+	}
+	job.Stdout.Close()
+	job.Stderr.Close()
+	<-drained
+
+	job.mu.Lock()
+	job.stdoutHistory.closeSpill()
+	job.stderrHistory.closeSpill()
+	job.mu.Unlock()
 
 	// Clean up after the job is complete
 	err := cleanupCgroup(job)
 	if err != nil {
 		logger.Warn("failed to cleanup cgroup", "job_id", job.ID, "pid", job.PID, "error", err)
 	}
+	job.detachNetwork()
 
-	job.Stdout.Close()
-	job.Stderr.Close()
+	close(job.done)
 }
 
-// GetJobOutput returns the output history of a job
-func (m *JobManager) GetJobOutput(jobID string) (stdout, stderr []byte, err error) {
-	value, exists := m.jobs.Load(jobID)
-	if !exists {
-		return nil, nil, fmt.Errorf("job %s not found", jobID)
+// pumpOutput copies one output stream into the job history and fans it out
+// to the current subscribers. A subscriber whose callback fails is dropped.
+func (job *Job) pumpOutput(wg *sync.WaitGroup, reader io.Reader, history *outputHistory, isStderr bool) {
+	defer wg.Done()
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			data := buffer[:n]
+			job.mu.Lock()
+			dropped := history.Dropped()
+			_, _ = history.Write(data)
+			if job.log != nil {
+				if err := job.log.Write(data, isStderr); err != nil {
+					logger.Warn("failed to persist job output", "job_id", job.ID, "error", err)
+				}
+			}
+			if dropped == 0 && history.Dropped() > 0 {
+				logger.Warn("job output exceeded history limit, dropping oldest output", "job_id", job.ID, "pid", job.PID, "stderr", isStderr)
+			}
+			subscribers := make(map[int]*subscriber, len(job.subscribers))
+			for id, sub := range job.subscribers {
+				subscribers[id] = sub
+			}
+			job.mu.Unlock()
+			for id, sub := range subscribers {
+				if err := sub.send(data, isStderr); err != nil {
+					logger.Warn("failed to send callback", "job_id", job.ID, "pid", job.PID, "error", err)
+					job.unsubscribe(id)
+				}
+			}
+		}
+		if err == io.EOF || errors.Is(err, os.ErrClosed) {
+			break
+		}
+		if err != nil {
+			logger.Warn("failed to read job output", "job_id", job.ID, "pid", job.PID, "error", err)
+			break
+		}
+	}
+}
+
+// OpenJobOutput returns readers of the output history of a job, as it is
+// now. Output of jobs from previous server runs is read back from the log
+// store, if any. The caller closes both readers.
+func (m *JobManager) OpenJobOutput(jobID string) (stdout, stderr *OutputReader, err error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.recovered {
+		if m.logStore == nil {
+			return &OutputReader{}, &OutputReader{}, nil
+		}
+		return m.logStore.OpenOutput(jobID)
 	}
 
-	job := value.(*Job)
 	job.mu.Lock()
 	defer job.mu.Unlock()
-	stdout = append([]byte(nil), job.stdoutHistory.Bytes()...)
-	stderr = append([]byte(nil), job.stderrHistory.Bytes()...)
+	if stdout, err = job.stdoutHistory.reader(); err != nil {
+		return nil, nil, err
+	}
+	if stderr, err = job.stderrHistory.reader(); err != nil {
+		stdout.Close()
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// GetJobOutput returns the whole output history of a job in memory. Use
+// OpenJobOutput for output of unknown size.
+func (m *JobManager) GetJobOutput(jobID string) (stdout, stderr []byte, err error) {
+	stdoutReader, stderrReader, err := m.OpenJobOutput(jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stdoutReader.Close()
+	defer stderrReader.Close()
+
+	if stdout, err = io.ReadAll(stdoutReader); err != nil {
+		return nil, nil, fmt.Errorf("failed to read job output: %v", err)
+	}
+	if stderr, err = io.ReadAll(stderrReader); err != nil {
+		return nil, nil, fmt.Errorf("failed to read job output: %v", err)
+	}
 	return stdout, stderr, nil
 }
 
@@ -343,28 +1042,112 @@ func (m *JobManager) ListJobs() []*Job {
 	return jobs
 }
 
-// KillJob forcefully terminates a running job.
-// Closing stdout/stderr here may race with serveSubscribers reads; fixing that
-// requires a broader lifecycle refactor and is intentionally out of scope.
-func (m *JobManager) KillJob(jobID string) error {
-	value, exists := m.jobs.LoadAndDelete(jobID)
-	if !exists {
-		return fmt.Errorf("job %s not found", jobID)
+// RemoveJob forgets a finished job together with its output history.
+func (m *JobManager) RemoveJob(jobID string) error {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return err
 	}
 
-	job := value.(*Job)
+	if !job.Status().State.Finished() {
+		return fmt.Errorf("job %s is still running, stop it first", jobID)
+	}
+	m.forget(job)
+	return nil
+}
 
-	if err := job.Cmd.Process.Signal(syscall.SIGKILL); err != nil {
-		return fmt.Errorf("failed to kill job %s: %v", jobID, err)
+// forget drops a finished job from memory and deletes everything stored
+// for it.
+func (m *JobManager) forget(job *Job) {
+	m.jobs.Delete(job.ID)
+	job.removeLayer()
+	if !job.recovered {
+		job.releaseHistory()
+	}
+	if m.registry != nil {
+		if err := m.registry.Delete(job.ID); err != nil {
+			logger.Warn("failed to remove job record", "job_id", job.ID, "error", err)
+		}
+	}
+	if m.logStore != nil {
+		if err := m.logStore.Remove(job.ID); err != nil {
+			logger.Warn("failed to remove job logs", "job_id", job.ID, "error", err)
+		}
 	}
+}
 
-	err := cleanupCgroup(job)
+// releaseHistory deletes any output the job spilled to disk.
+func (job *Job) releaseHistory() {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.stdoutHistory.release()
+	job.stderrHistory.release()
+}
+
+// PruneFilter selects finished jobs for PruneJobs. Zero fields match everything.
+type PruneFilter struct {
+	// Owner restricts pruning to jobs started by this user.
+	Owner string
+	// OlderThan restricts pruning to jobs that finished at least this long ago.
+	OlderThan time.Duration
+	// States restricts pruning to jobs in one of these final states.
+	States []State
+}
+
+func (f PruneFilter) matches(job *Job, now time.Time) bool {
+	status := job.Status()
+	if !status.State.Finished() {
+		return false
+	}
+	if f.Owner != "" && job.Owner != f.Owner {
+		return false
+	}
+	if f.OlderThan > 0 && now.Sub(status.FinishedAt) < f.OlderThan {
+		return false
+	}
+	if len(f.States) == 0 {
+		return true
+	}
+	for _, state := range f.States {
+		if status.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneJobs removes the finished jobs matching filter and returns their IDs.
+func (m *JobManager) PruneJobs(filter PruneFilter) []string {
+	now := time.Now()
+	var removed []string
+	m.jobs.Range(func(key, value interface{}) bool {
+		job := value.(*Job)
+		if filter.matches(job, now) {
+			m.forget(job)
+			removed = append(removed, job.ID)
+		}
+		return true
+	})
+
+	return removed
+}
+
+// KillJob forcefully terminates every process of a running job with
+// SIGKILL. The job's owner goroutine reaps the process and removes its
+// cgroup once it exits.
+func (m *JobManager) KillJob(jobID string) error {
+	job, err := m.GetJob(jobID)
 	if err != nil {
 		return err
 	}
 
-	job.Stdout.Close()
-	job.Stderr.Close()
+	if !job.Status().IsRunning() {
+		return fmt.Errorf("job %s is not running", jobID)
+	}
+
+	if err := signalJob(job, syscall.SIGKILL); err != nil {
+		return fmt.Errorf("failed to kill job %s: %v", jobID, err)
+	}
 
 	return nil
 }
@@ -372,6 +1155,9 @@ func (m *JobManager) KillJob(jobID string) error {
 func (m *JobManager) KillJobsAll() {
 	m.jobs.Range(func(key, value interface{}) bool {
 		job := value.(*Job)
+		if !job.Status().IsRunning() {
+			return true
+		}
 		if err := m.KillJob(job.ID); err != nil {
 			logger.Warn("failed to kill job during cleanup", "job_id", job.ID, "pid", job.PID, "error", err)
 		}
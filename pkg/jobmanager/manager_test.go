@@ -4,9 +4,21 @@ package jobmanager
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
 )
 
 func TestNewReturnsManager(t *testing.T) {
@@ -17,15 +29,11 @@ func TestNewReturnsManager(t *testing.T) {
 
 func TestStartJobNoLimitsCapturesOutputAndListsJob(t *testing.T) {
 	manager := New()
-	job, err := manager.StartJob("/bin/echo", []string{"hello"}, "", "", "", "", "")
+	job, err := manager.StartJob("alice", "/bin/echo", []string{"hello"}, limits.Limits{}, StartOptions{})
 	if err != nil {
 		t.Fatalf("StartJob() error = %v", err)
 	}
-	defer func() {
-		if err := job.Cmd.Wait(); err != nil {
-			t.Logf("waiting for job exit: %v", err)
-		}
-	}()
+	defer func() { <-job.Done() }()
 
 	if job.ID == "" {
 		t.Fatal("StartJob() returned job with empty ID")
@@ -33,6 +41,9 @@ func TestStartJobNoLimitsCapturesOutputAndListsJob(t *testing.T) {
 	if job.Command != "/bin/echo" {
 		t.Fatalf("job.Command = %q, want /bin/echo", job.Command)
 	}
+	if job.Owner != "alice" {
+		t.Fatalf("job.Owner = %q, want alice", job.Owner)
+	}
 	if job.PID <= 0 {
 		t.Fatalf("job.PID = %d, want > 0", job.PID)
 	}
@@ -66,38 +77,794 @@ func TestUnknownJobErrors(t *testing.T) {
 	manager := New()
 	unknownID := "missing-job"
 
-	if running, err := manager.GetJobStatus(unknownID); err == nil || running {
-		t.Fatalf("GetJobStatus(%q) = (%v, %v), want error and running=false", unknownID, running, err)
+	if status, err := manager.GetJobStatus(unknownID); err == nil || status.IsRunning() {
+		t.Fatalf("GetJobStatus(%q) = (%v, %v), want error and running=false", unknownID, status, err)
+	}
+	if job, err := manager.GetJob(unknownID); err == nil || job != nil {
+		t.Fatalf("GetJob(%q) = (%v, %v), want nil job and error", unknownID, job, err)
 	}
-	if err := manager.StopJob(unknownID); err == nil {
+	if _, err := manager.StopJob(unknownID, 0); err == nil {
 		t.Fatalf("StopJob(%q) succeeded, want error", unknownID)
 	}
+	if err := manager.RemoveJob(unknownID); err == nil {
+		t.Fatalf("RemoveJob(%q) succeeded, want error", unknownID)
+	}
 	if err := manager.KillJob(unknownID); err == nil {
 		t.Fatalf("KillJob(%q) succeeded, want error", unknownID)
 	}
 	if stdout, stderr, err := manager.GetJobOutput(unknownID); err == nil || stdout != nil || stderr != nil {
 		t.Fatalf("GetJobOutput(%q) = (%q, %q, %v), want nil output and error", unknownID, stdout, stderr, err)
 	}
+	if _, err := manager.WaitJob(context.Background(), unknownID); err == nil {
+		t.Fatalf("WaitJob(%q) succeeded, want error", unknownID)
+	}
 	if err := manager.StreamOutput(context.Background(), unknownID, func([]byte, bool) error { return nil }); err == nil {
 		t.Fatalf("StreamOutput(%q) succeeded, want error", unknownID)
 	}
 }
 
+func waitForJob(t *testing.T, job *Job) {
+	t.Helper()
+	select {
+	case <-job.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("job %s did not finish", job.ID)
+	}
+}
+
 func TestGetJobStatusReturnsFalseAfterExit(t *testing.T) {
 	manager := New()
-	job, err := manager.StartJob("/bin/sh", []string{"-c", "exit 0"}, "", "", "", "", "")
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "exit 0"}, limits.Limits{}, StartOptions{})
 	if err != nil {
 		t.Fatalf("StartJob() error = %v", err)
 	}
-	if err := job.Cmd.Wait(); err != nil {
-		t.Fatalf("waiting for job exit: %v", err)
-	}
+	waitForJob(t, job)
 
-	running, err := manager.GetJobStatus(job.ID)
+	status, err := manager.GetJobStatus(job.ID)
 	if err != nil {
 		t.Fatalf("GetJobStatus() error = %v", err)
 	}
-	if running {
+	if status.IsRunning() {
 		t.Fatal("GetJobStatus() running = true, want false after process exits")
 	}
+	if status.State != StateExited || status.ExitCode != 0 {
+		t.Fatalf("status = %+v, want exited with code 0", status)
+	}
+	if status.StartedAt.IsZero() || status.FinishedAt.Before(status.StartedAt) {
+		t.Fatalf("timestamps = %v..%v, want started before finished", status.StartedAt, status.FinishedAt)
+	}
+}
+
+func TestGetJobStatusRecordsExitCode(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "exit 3"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	if status := job.Status(); status.State != StateExited || status.ExitCode != 3 {
+		t.Fatalf("status = %+v, want exited with code 3", status)
+	}
+}
+
+func TestGetJobStatusRecordsSignal(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "kill -TERM $$"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	status := job.Status()
+	if status.State != StateKilled || status.Signal != syscall.SIGTERM || status.ExitCode != -1 {
+		t.Fatalf("status = %+v, want killed by SIGTERM", status)
+	}
+}
+
+func TestWaitJob(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "sleep 0.1; exit 4"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := manager.WaitJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("WaitJob() error = %v", err)
+	}
+	if status.State != StateExited || status.ExitCode != 4 {
+		t.Fatalf("status = %+v, want exited with code 4", status)
+	}
+}
+
+func TestWaitJobContextDeadline(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sleep", []string{"5"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	defer func() {
+		if err := manager.KillJob(job.ID); err != nil {
+			t.Logf("killing job: %v", err)
+		}
+		<-job.Done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	status, err := manager.WaitJob(ctx, job.ID)
+	if !errors.Is(err, context.DeadlineExceeded) || !status.IsRunning() {
+		t.Fatalf("WaitJob() = (%+v, %v), want running and deadline exceeded", status, err)
+	}
+}
+
+func TestStartJobFailureIsRecorded(t *testing.T) {
+	manager := New()
+	if _, err := manager.StartJob("alice", "/nonexistent/command", nil, limits.Limits{}, StartOptions{}); err == nil {
+		t.Fatal("StartJob() succeeded, want error")
+	}
+
+	jobs := manager.ListJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("ListJobs() returned %d jobs, want 1", len(jobs))
+	}
+	status := jobs[0].Status()
+	if status.State != StateFailedToStart || status.Error == "" {
+		t.Fatalf("status = %+v, want failed-to-start with error", status)
+	}
+	if err := manager.KillJob(jobs[0].ID); err == nil {
+		t.Fatal("KillJob() on a job that never started succeeded, want error")
+	}
+}
+
+func TestJobIsReapedWithoutSubscribers(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/true", nil, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	if err := syscall.Kill(job.PID, 0); !errors.Is(err, syscall.ESRCH) {
+		t.Fatalf("kill(%d, 0) = %v, want ESRCH for a reaped process", job.PID, err)
+	}
+}
+
+func TestConcurrentSubscribersReceiveOutput(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "sleep 0.2; echo hello"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+
+	const subscribers = 3
+	var mu sync.Mutex
+	received := make([]string, subscribers)
+	errs := make(chan error, subscribers+1)
+	for i := 0; i < subscribers; i++ {
+		go func(i int) {
+			errs <- manager.StreamOutput(context.Background(), job.ID, func(data []byte, isStderr bool) error {
+				mu.Lock()
+				defer mu.Unlock()
+				received[i] += string(data)
+				return nil
+			})
+		}(i)
+	}
+	// A failing subscriber must not stop delivery to the others
+	go func() {
+		errs <- manager.StreamOutput(context.Background(), job.ID, func([]byte, bool) error {
+			return errors.New("client went away")
+		})
+	}()
+
+	for i := 0; i < subscribers+1; i++ {
+		select {
+		case <-errs:
+		case <-time.After(5 * time.Second):
+			t.Fatal("StreamOutput did not return after the job finished")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, out := range received {
+		if out != "hello\n" {
+			t.Errorf("subscriber %d received %q, want %q", i, out, "hello\n")
+		}
+	}
+}
+
+func TestJobFinishesWhenDescendantHoldsOutput(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "sleep 30 & echo started"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	defer func() {
+		if err := syscall.Kill(-job.PID, syscall.SIGKILL); err != nil {
+			t.Logf("killing process group: %v", err)
+		}
+	}()
+
+	select {
+	case <-job.Done():
+	case <-time.After(outputDrainTimeout + 5*time.Second):
+		t.Fatal("job did not finish while a descendant held its output open")
+	}
+	if status := job.Status(); status.State != StateExited {
+		t.Fatalf("status = %+v, want exited", status)
+	}
+	stdout, _, err := manager.GetJobOutput(job.ID)
+	if err != nil || string(stdout) != "started\n" {
+		t.Fatalf("GetJobOutput() = (%q, %v), want started", stdout, err)
+	}
+}
+
+func TestNewAppliesOptions(t *testing.T) {
+	if got := New().stopGracePeriod; got != DefaultStopGracePeriod {
+		t.Fatalf("default stopGracePeriod = %v, want %v", got, DefaultStopGracePeriod)
+	}
+	if got := New(WithStopGracePeriod(time.Second)).stopGracePeriod; got != time.Second {
+		t.Fatalf("stopGracePeriod = %v, want 1s", got)
+	}
+}
+
+func TestStopJobTerminatesProcessGroup(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "sleep 30 & echo $!; wait"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+
+	var child int
+	deadline := time.Now().Add(2 * time.Second)
+	for child == 0 && time.Now().Before(deadline) {
+		stdout, _, _ := manager.GetJobOutput(job.ID)
+		child, _ = strconv.Atoi(strings.TrimSpace(string(stdout)))
+		time.Sleep(10 * time.Millisecond)
+	}
+	if child == 0 {
+		t.Fatal("job did not report its child PID")
+	}
+
+	result, err := manager.StopJob(job.ID, time.Second)
+	if err != nil {
+		t.Fatalf("StopJob() error = %v", err)
+	}
+	if result.Escalated || result.Status.State != StateKilled || result.Status.Signal != syscall.SIGTERM {
+		t.Fatalf("result = %+v, want terminated by SIGTERM without escalation", result)
+	}
+
+	// The orphaned child is reparented and reaped by init; it must be gone or a zombie.
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", child)); err != nil || strings.Contains(string(stat), ") Z ") {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("child %d of the job survived StopJob", child)
+}
+
+func TestStopJobEscalatesToSIGKILL(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "trap '' TERM; echo ready; while :; do sleep 0.05; done"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stdout, _, _ := manager.GetJobOutput(job.ID); strings.Contains(string(stdout), "ready") {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	result, err := manager.StopJob(job.ID, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("StopJob() error = %v", err)
+	}
+	if !result.Escalated || result.Status.Signal != syscall.SIGKILL {
+		t.Fatalf("result = %+v, want escalation to SIGKILL", result)
+	}
+}
+
+func TestKilledJobKeepsStatusAndOutput(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "echo before; sleep 30"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stdout, _, _ := manager.GetJobOutput(job.ID); len(stdout) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := manager.KillJob(job.ID); err != nil {
+		t.Fatalf("KillJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	status, err := manager.GetJobStatus(job.ID)
+	if err != nil || status.State != StateKilled {
+		t.Fatalf("GetJobStatus() = (%+v, %v), want killed", status, err)
+	}
+	stdout, _, err := manager.GetJobOutput(job.ID)
+	if err != nil || string(stdout) != "before\n" {
+		t.Fatalf("GetJobOutput() = (%q, %v), want output kept after kill", stdout, err)
+	}
+}
+
+func TestPendingJobIsNotSignalled(t *testing.T) {
+	manager := New()
+	// A job listed before its process started has no PID; signalling it
+	// would signal the test's own process group
+	job := &Job{ID: "pending", status: Status{State: StatePending}, done: make(chan struct{})}
+	manager.jobs.Store(job.ID, job)
+
+	if _, err := manager.StopJob(job.ID, time.Second); err == nil {
+		t.Error("StopJob() of a pending job succeeded, want error")
+	}
+	if err := manager.KillJob(job.ID); err == nil {
+		t.Error("KillJob() of a pending job succeeded, want error")
+	}
+	manager.KillJobsAll()
+	if err := signalJob(job, syscall.SIGTERM); err == nil {
+		t.Error("signalJob() without a PID succeeded, want error")
+	}
+}
+
+func TestRemoveJob(t *testing.T) {
+	manager := New()
+	job, err := manager.StartJob("alice", "/bin/sleep", []string{"30"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+
+	if err := manager.RemoveJob(job.ID); err == nil {
+		t.Fatal("RemoveJob() on a running job succeeded, want error")
+	}
+	if err := manager.KillJob(job.ID); err != nil {
+		t.Fatalf("KillJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	if err := manager.RemoveJob(job.ID); err != nil {
+		t.Fatalf("RemoveJob() error = %v", err)
+	}
+	if _, err := manager.GetJob(job.ID); err == nil {
+		t.Fatal("GetJob() found a removed job")
+	}
+}
+
+func TestPruneJobs(t *testing.T) {
+	manager := New()
+	start := func(owner, script string) *Job {
+		job, err := manager.StartJob(owner, "/bin/sh", []string{"-c", script}, limits.Limits{}, StartOptions{})
+		if err != nil {
+			t.Fatalf("StartJob() error = %v", err)
+		}
+		return job
+	}
+	running := start("alice", "sleep 30")
+	defer func() {
+		if err := manager.KillJob(running.ID); err != nil {
+			t.Logf("killing job: %v", err)
+		}
+		// Later tests swap the cgroup root the job's cleanup reads
+		<-running.Done()
+	}()
+	aliceExited := start("alice", "exit 0")
+	aliceFailed := start("alice", "exit 1")
+	bobExited := start("bob", "exit 0")
+	for _, job := range []*Job{aliceExited, aliceFailed, bobExited} {
+		waitForJob(t, job)
+	}
+
+	if removed := manager.PruneJobs(PruneFilter{OlderThan: time.Hour}); len(removed) != 0 {
+		t.Fatalf("PruneJobs(OlderThan: 1h) removed %v, want nothing", removed)
+	}
+
+	removed := manager.PruneJobs(PruneFilter{Owner: "alice"})
+	sort.Strings(removed)
+	want := []string{aliceExited.ID, aliceFailed.ID}
+	sort.Strings(want)
+	if !reflect.DeepEqual(removed, want) {
+		t.Fatalf("PruneJobs(Owner: alice) = %v, want %v", removed, want)
+	}
+
+	if _, err := manager.GetJob(running.ID); err != nil {
+		t.Fatal("PruneJobs() removed a running job")
+	}
+	if _, err := manager.GetJob(bobExited.ID); err != nil {
+		t.Fatal("PruneJobs() removed a job of another owner")
+	}
+}
+
+func TestJobOutputSpillsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	manager := New(WithHistoryLimit(64), WithSpillDir(dir))
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "seq 1 1000"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	var want strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&want, "%d\n", i)
+	}
+	stdout, _, err := manager.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() error = %v", err)
+	}
+	if string(stdout) != want.String() {
+		t.Fatalf("GetJobOutput() returned %d bytes, want %d", len(stdout), want.Len())
+	}
+
+	if err := manager.RemoveJob(job.ID); err != nil {
+		t.Fatalf("RemoveJob() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("spill files left after RemoveJob: %v", entries)
+	}
+}
+
+func TestJobsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(filepath.Join(dir, "logs"))
+	if err != nil {
+		t.Fatalf("NewLogStore() error = %v", err)
+	}
+	registry, err := NewRegistry(filepath.Join(dir, "jobs"))
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	manager := New(WithLogStore(store), WithRegistry(registry))
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "echo out; echo err >&2; exit 3"}, limits.Limits{}, StartOptions{})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	waitForJob(t, job)
+
+	// Wait for the final record written after the job finishes
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		records, _ := registry.Load()
+		if len(records) == 1 && records[0].Status.State.Finished() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("final job record was not persisted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	restarted := New(WithLogStore(store), WithRegistry(registry))
+	t.Cleanup(restarted.Close)
+	if err := restarted.Recover(); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if jobs := restarted.ListJobs(); len(jobs) != 1 || jobs[0].Owner != "alice" {
+		t.Fatalf("ListJobs() after restart = %v, want the finished job", jobs)
+	}
+	status, err := restarted.GetJobStatus(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobStatus() after restart error = %v", err)
+	}
+	if status.State != StateExited || status.ExitCode != 3 {
+		t.Fatalf("status after restart = %+v, want exited with code 3", status)
+	}
+
+	stdout, stderr, err := restarted.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() after restart error = %v", err)
+	}
+	if string(stdout) != "out\n" || string(stderr) != "err\n" {
+		t.Fatalf("output after restart = (%q, %q), want (%q, %q)", stdout, stderr, "out\n", "err\n")
+	}
+
+	if err := restarted.KillJob(job.ID); err == nil {
+		t.Fatal("KillJob() of a finished job succeeded, want error")
+	}
+	if removed := restarted.PruneJobs(PruneFilter{Owner: "alice"}); len(removed) != 1 || removed[0] != job.ID {
+		t.Fatalf("PruneJobs() after restart = %v, want [%s]", removed, job.ID)
+	}
+	if records, _ := registry.Load(); len(records) != 0 {
+		t.Fatalf("registry after prune = %v, want empty", records)
+	}
+}
+
+func TestRecoverAdoptsRunningJobs(t *testing.T) {
+	registry, err := NewRegistry(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	// A job left running by a previous server run
+	cmd := exec.Command("/bin/sleep", "30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	reaped := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(reaped)
+	}()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	running := jobRecord{ID: "running", Owner: "alice", Command: "/bin/sleep", PID: cmd.Process.Pid, CgroupPath: getCgroupPath("running"), Status: Status{State: StateRunning}}
+	lost := jobRecord{ID: "lost", Owner: "alice", Command: "/bin/sleep", PID: 0, CgroupPath: getCgroupPath("lost"), Status: Status{State: StateRunning}}
+	for _, record := range []jobRecord{running, lost} {
+		if err := registry.Save(record); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	manager := New(WithRegistry(registry))
+	// Stop the watcher of the adopted job before other tests replace
+	// cgroupBasePath
+	t.Cleanup(manager.Close)
+	if err := manager.Recover(); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	if status, _ := manager.GetJobStatus("lost"); status.State != StateLost {
+		t.Fatalf("state of a job without processes = %s, want %s", status.State, StateLost)
+	}
+	if status, _ := manager.GetJobStatus("running"); status.State != StateRunning {
+		t.Fatalf("state of an adopted job = %s, want %s", status.State, StateRunning)
+	}
+
+	if err := manager.KillJob("running"); err != nil {
+		t.Fatalf("KillJob() of an adopted job error = %v", err)
+	}
+	<-reaped
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := manager.WaitJob(ctx, "running")
+	if err != nil {
+		t.Fatalf("WaitJob() error = %v", err)
+	}
+	if status.State != StateKilled || status.Signal != syscall.SIGKILL {
+		t.Fatalf("adopted job status = %+v, want killed by SIGKILL", status)
+	}
+}
+
+func TestRecoveredJobKeepsWritingOutput(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(filepath.Join(dir, "logs"))
+	if err != nil {
+		t.Fatalf("NewLogStore() error = %v", err)
+	}
+	registry, err := NewRegistry(filepath.Join(dir, "jobs"))
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	// A job of a previous server run, whose output pipes lost their reader
+	// when that server exited
+	log, err := store.Create("adopted")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	stdout, stdoutWriter, err := log.createPipe(false)
+	if err != nil {
+		t.Fatalf("createPipe() error = %v", err)
+	}
+	stderr, stderrWriter, err := log.createPipe(true)
+	if err != nil {
+		t.Fatalf("createPipe() error = %v", err)
+	}
+	cmd := exec.Command("/bin/sh", "-c", "sleep 0.2; echo before; echo error >&2; echo after; exec sleep 30")
+	cmd.Stdout, cmd.Stderr = stdoutWriter, stderrWriter
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []*os.File{stdout, stdoutWriter, stderr, stderrWriter} {
+		f.Close()
+	}
+	log.Close()
+	reaped := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(reaped)
+	}()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	record := jobRecord{ID: "adopted", Owner: "alice", Command: "/bin/sh", PID: cmd.Process.Pid, CgroupPath: getCgroupPath("adopted"), Status: Status{State: StateRunning}}
+	if err := registry.Save(record); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The job writes while no server reads its output
+	select {
+	case <-reaped:
+		t.Fatalf("job died writing its output without a reader: %v", cmd.ProcessState)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	manager := New(WithRegistry(registry), WithLogStore(store))
+	t.Cleanup(manager.Close)
+	if err := manager.Recover(); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	var gotStdout, gotStderr []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if gotStdout, gotStderr, err = manager.GetJobOutput("adopted"); err != nil {
+			t.Fatalf("GetJobOutput() error = %v", err)
+		}
+		if string(gotStdout) == "before\nafter\n" && string(gotStderr) == "error\n" {
+			break
+		}
+	}
+	if string(gotStdout) != "before\nafter\n" || string(gotStderr) != "error\n" {
+		t.Fatalf("output = %q, %q, want the output written while no server ran", gotStdout, gotStderr)
+	}
+	if status, _ := manager.GetJobStatus("adopted"); status.State != StateRunning {
+		t.Fatalf("state of the adopted job = %s, want %s", status.State, StateRunning)
+	}
+
+	if err := manager.KillJob("adopted"); err != nil {
+		t.Fatalf("KillJob() error = %v", err)
+	}
+	<-reaped
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := manager.WaitJob(ctx, "adopted"); err != nil {
+		t.Fatalf("WaitJob() error = %v", err)
+	}
+}
+
+func TestCloseStopsWatchersOfAdoptedJobs(t *testing.T) {
+	// The fake cgroup has a member, so the job counts as running
+	root := fakeCgroupRoot(t, "sentry-run-adopted")
+	writeMemoryEvents := func() {
+		if err := os.WriteFile(filepath.Join(root, "sentry-run-adopted", "memory.events"), []byte("oom 0\noom_kill 0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeMemoryEvents()
+	if err := os.WriteFile(filepath.Join(root, "sentry-run-adopted", "cgroup.procs"), []byte("4242\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := NewRegistry(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	record := jobRecord{ID: "adopted", Owner: "alice", Command: "/bin/sleep", PID: 4242, CgroupPath: getCgroupPath("adopted"), Limits: limits.Limits{MemoryMax: 10 << 20}, Status: Status{State: StateRunning}}
+	if err := registry.Save(record); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	manager := New(WithRegistry(registry))
+	if err := manager.Recover(); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	writeMemoryEvents()
+
+	closed := make(chan struct{})
+	go func() {
+		manager.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() did not return while an adopted job runs")
+	}
+	if status, _ := manager.GetJobStatus("adopted"); status.State != StateRunning {
+		t.Fatalf("state after Close = %s, want the job left running", status.State)
+	}
+}
+
+func TestStartJobFailsWhenCgroupPlacementFails(t *testing.T) {
+	// A plain directory is not a cgroup, so the child cannot be cloned into it
+	fakeCgroupRoot(t)
+	marker := filepath.Join(t.TempDir(), "ran")
+
+	manager := New()
+	_, err := manager.StartJob("alice", "/bin/touch", []string{marker}, limits.Limits{MemoryMax: 10 << 20}, StartOptions{})
+	if err == nil {
+		t.Fatal("StartJob() succeeded, want placement error")
+	}
+
+	jobs := manager.ListJobs()
+	if len(jobs) != 1 || jobs[0].Status().State != StateFailedToStart {
+		t.Fatalf("jobs = %v, want one failed-to-start job", jobs)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("job command ran outside its cgroup: %v", err)
+	}
+}
+
+func TestSetLimitsWritesInterfaceFiles(t *testing.T) {
+	dir := t.TempDir()
+	swap := uint64(0)
+	err := setLimits(dir, limits.Limits{
+		MemoryMax:     1 << 30,
+		MemoryHigh:    limits.Max,
+		MemorySwapMax: &swap,
+		CPUQuota:      50000,
+		CPUPeriod:     100000,
+		CPUWeight:     200,
+		PidsMax:       64,
+		IO:            []limits.IOLimit{{Device: "259:0", WriteBps: 1 << 20, ReadIOPS: 100}},
+	})
+	if err != nil {
+		t.Fatalf("setLimits() error = %v", err)
+	}
+
+	want := map[string]string{
+		"memory.max":      "1073741824",
+		"memory.high":     "max",
+		"memory.swap.max": "0",
+		"cpu.max":         "50000 100000",
+		"cpu.weight":      "200",
+		"pids.max":        "64",
+		"io.max":          "259:0 wbps=1048576 riops=100",
+	}
+	for name, value := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != value {
+			t.Errorf("%s = %q (%v), want %q", name, data, err, value)
+		}
+	}
+}
+
+func TestStartJobAppliesDefaultPidsLimit(t *testing.T) {
+	// Placement fails in a fake cgroup root, but the job keeps its limits
+	fakeCgroupRoot(t)
+
+	tests := []struct {
+		limits limits.Limits
+		want   uint64
+	}{
+		{limits.Limits{}, 1024},
+		{limits.Limits{PidsMax: limits.Max}, limits.Max},
+	}
+	for _, tt := range tests {
+		manager := New(WithDefaultPidsLimit(1024))
+		manager.StartJob("alice", "/bin/true", nil, tt.limits, StartOptions{})
+		jobs := manager.ListJobs()
+		if len(jobs) != 1 || jobs[0].Limits.PidsMax != tt.want {
+			t.Fatalf("jobs = %v, want one job with PidsMax %d", jobs, tt.want)
+		}
+	}
+}
+
+func TestGetJobPids(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+	if err := os.WriteFile(filepath.Join(root, "sentry-run-job-1", "pids.current"), []byte("3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := New()
+	job := &Job{ID: "job-1", done: make(chan struct{}), status: Status{State: StateRunning}}
+	manager.jobs.Store(job.ID, job)
+
+	pids, err := manager.GetJobPids("job-1")
+	if err != nil || pids != 3 {
+		t.Fatalf("GetJobPids() = (%d, %v), want 3", pids, err)
+	}
+
+	job.status.State = StateExited
+	if pids, err := manager.GetJobPids("job-1"); err != nil || pids != 0 {
+		t.Fatalf("GetJobPids() after exit = (%d, %v), want 0", pids, err)
+	}
 }
@@ -0,0 +1,228 @@
+package jobmanager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// NetworkMode selects the network namespace a job runs in.
+type NetworkMode string
+
+const (
+	// NetworkHost shares the host's network namespace.
+	NetworkHost NetworkMode = "host"
+	// NetworkNone gives the job a network namespace with only loopback.
+	NetworkNone NetworkMode = "none"
+	// NetworkBridged gives the job a network namespace connected to the
+	// server's bridge through a veth pair, with outbound traffic NATed.
+	NetworkBridged NetworkMode = "bridged"
+)
+
+// ParseNetworkMode parses a network mode. The empty string is NetworkHost.
+func ParseNetworkMode(s string) (NetworkMode, error) {
+	switch mode := NetworkMode(s); mode {
+	case "":
+		return NetworkHost, nil
+	case NetworkHost, NetworkNone, NetworkBridged:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid network mode %q, want host, none or bridged", s)
+}
+
+// DefaultBridgeName and DefaultBridgeSubnet describe the bridge bridged jobs
+// are attached to unless configured with WithBridge.
+const DefaultBridgeName = "sentry0"
+
+var DefaultBridgeSubnet = netip.MustParsePrefix("10.88.0.0/16")
+
+// hostResolvConfs are the files the default resolvers of bridged jobs are
+// read from, in order. The second lists the upstream servers of
+// systemd-resolved, whose stub on 127.0.0.53 jobs cannot reach.
+var hostResolvConfs = []string{"/etc/resolv.conf", "/run/systemd/resolve/resolv.conf"}
+
+// bridgeRuntimeDir holds the resolv.conf files of bridges.
+const bridgeRuntimeDir = "/run/sentry-run"
+
+// blockedDestinations are the ranges bridged jobs cannot reach: private
+// networks, shared address space and link-local addresses, which include
+// cloud metadata services.
+var blockedDestinations = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+}
+
+// initNetwork tells the init of an isolated job how to configure its
+// network namespace.
+type initNetwork struct {
+	Mode NetworkMode `json:"mode"`
+	// Link is the name of the job's end of its veth pair, which the init
+	// renames to eth0 and gives Address and a default route via Gateway
+	Link    string `json:"link,omitempty"`
+	Address string `json:"address,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+	// ResolvConf is bind-mounted over the job's /etc/resolv.conf
+	ResolvConf string `json:"resolv_conf,omitempty"`
+}
+
+// vethPair is the veth pair connecting a bridged job to the bridge.
+type vethPair struct {
+	Host    string
+	Peer    string
+	Address netip.Prefix
+}
+
+// bridgeNetwork is the bridge bridged jobs are attached to. The bridge and
+// its NAT rule are set up when the first bridged job starts.
+type bridgeNetwork struct {
+	name    string
+	subnet  netip.Prefix
+	gateway netip.Addr
+	// dns are the resolvers jobs may query, listed in resolvConf
+	dns        []netip.Addr
+	resolvConf string
+
+	mu    sync.Mutex
+	ready bool
+	// inUse maps the addresses of running bridged jobs to their jobs and
+	// next is where the search for a free address resumes
+	inUse map[netip.Addr]string
+	next  netip.Addr
+}
+
+func newBridgeNetwork(name string, subnet netip.Prefix, dns []netip.Addr) (*bridgeNetwork, error) {
+	subnet = subnet.Masked()
+	if !subnet.Addr().Is4() || subnet.Bits() > 30 {
+		return nil, fmt.Errorf("bridge subnet %s must be an IPv4 prefix of at most /30", subnet)
+	}
+	if name == "" || len(name) > 15 {
+		return nil, fmt.Errorf("invalid bridge name %q", name)
+	}
+	for _, server := range dns {
+		if !server.Is4() || server.IsLoopback() || server.IsUnspecified() {
+			return nil, fmt.Errorf("DNS server %s must be a non-loopback IPv4 address", server)
+		}
+	}
+	gateway := subnet.Addr().Next()
+	bridge := &bridgeNetwork{
+		name:    name,
+		subnet:  subnet,
+		gateway: gateway,
+		dns:     dns,
+		inUse:   make(map[netip.Addr]string),
+		next:    gateway.Next(),
+	}
+	if len(dns) > 0 {
+		bridge.resolvConf = filepath.Join(bridgeRuntimeDir, name+".resolv.conf")
+	}
+	return bridge, nil
+}
+
+// resolvConfData returns the resolv.conf of jobs on the bridge.
+func (b *bridgeNetwork) resolvConfData() []byte {
+	var data bytes.Buffer
+	for _, server := range b.dns {
+		fmt.Fprintf(&data, "nameserver %s\n", server)
+	}
+	return data.Bytes()
+}
+
+// hostResolvers returns the IPv4 nameservers of the first of
+// hostResolvConfs that lists any reachable from a job.
+func hostResolvers() []netip.Addr {
+	for _, path := range hostResolvConfs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if servers := parseNameservers(data); len(servers) > 0 {
+			return servers
+		}
+	}
+	return nil
+}
+
+// parseNameservers returns the IPv4 nameservers of a resolv.conf, except
+// loopback addresses, which are the host's own and not the job's.
+func parseNameservers(data []byte) []netip.Addr {
+	var servers []netip.Addr
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		server, err := netip.ParseAddr(fields[1])
+		if err != nil || !server.Is4() || server.IsLoopback() || server.IsUnspecified() {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	return servers
+}
+
+// allocate reserves a free address of the subnet for a job. The network
+// address, the gateway and the broadcast address are never handed out.
+func (b *bridgeNetwork) allocate(jobID string) (netip.Prefix, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start := b.next
+	if !b.usable(start) {
+		start = b.gateway.Next()
+	}
+	for addr := start; ; {
+		if _, taken := b.inUse[addr]; !taken {
+			b.inUse[addr] = jobID
+			b.next = addr.Next()
+			return netip.PrefixFrom(addr, b.subnet.Bits()), nil
+		}
+		if addr = addr.Next(); !b.usable(addr) {
+			addr = b.gateway.Next()
+		}
+		if addr == start {
+			return netip.Prefix{}, fmt.Errorf("no free address left in bridge subnet %s", b.subnet)
+		}
+	}
+}
+
+// usable reports whether addr may be assigned to a job.
+func (b *bridgeNetwork) usable(addr netip.Addr) bool {
+	if !b.subnet.Contains(addr) || addr.Compare(b.gateway) <= 0 {
+		return false
+	}
+	// The last address of the subnet is its broadcast address
+	return b.subnet.Contains(addr.Next())
+}
+
+// reserve marks the address of a job recovered after a restart as in use.
+func (b *bridgeNetwork) reserve(jobID string, address netip.Prefix) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inUse[address.Addr()] = jobID
+}
+
+// release returns a job's address to the pool.
+func (b *bridgeNetwork) release(address netip.Prefix) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.inUse, address.Addr())
+}
+
+// vethNames returns the names of a job's veth pair ends, which must fit in
+// IFNAMSIZ.
+func vethNames(jobID string) (host, peer string) {
+	id := jobID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return "sv" + id, "sp" + id
+}
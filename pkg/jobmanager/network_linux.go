@@ -0,0 +1,286 @@
+package jobmanager
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ipForwardPath enables routing between the bridge and the host's uplinks.
+// The setting is host-wide: forwarding is decided by the interface a packet
+// arrives on, and replies to jobs arrive on the uplinks.
+const ipForwardPath = "/proc/sys/net/ipv4/ip_forward"
+
+// setup creates the bridge with the gateway address, enables IPv4
+// forwarding, writes the jobs' resolv.conf and installs the NAT and filter
+// rules for the subnet, once. Forwarding stays enabled when the server
+// exits, like the bridge and its rules.
+func (b *bridgeNetwork) setup() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ready {
+		return nil
+	}
+
+	bridge, err := netlink.LinkByName(b.name)
+	if errors.As(err, &netlink.LinkNotFoundError{}) {
+		bridge = &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: b.name}}
+		err = netlink.LinkAdd(bridge)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create bridge %s: %v", b.name, err)
+	}
+	gateway := &netlink.Addr{IPNet: prefixToIPNet(netip.PrefixFrom(b.gateway, b.subnet.Bits()))}
+	if err := netlink.AddrReplace(bridge, gateway); err != nil {
+		return fmt.Errorf("failed to assign %s to bridge %s: %v", gateway, b.name, err)
+	}
+	if err := netlink.LinkSetUp(bridge); err != nil {
+		return fmt.Errorf("failed to bring up bridge %s: %v", b.name, err)
+	}
+
+	if forward, err := os.ReadFile(ipForwardPath); err != nil || strings.TrimSpace(string(forward)) != "1" {
+		if err := os.WriteFile(ipForwardPath, []byte("1"), 0644); err != nil {
+			return fmt.Errorf("failed to enable IPv4 forwarding: %v", err)
+		}
+		logger.Warn("enabled IPv4 forwarding on the host for bridged jobs", "sysctl", "net.ipv4.ip_forward")
+	}
+	if b.resolvConf != "" {
+		if err := os.MkdirAll(filepath.Dir(b.resolvConf), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %v", filepath.Dir(b.resolvConf), err)
+		}
+		if err := os.WriteFile(b.resolvConf, b.resolvConfData(), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", b.resolvConf, err)
+		}
+	}
+	if err := b.setupRules(); err != nil {
+		return fmt.Errorf("failed to set up nftables rules for bridge %s: %v", b.name, err)
+	}
+	b.ready = true
+	return nil
+}
+
+// setupRules (re)creates an nftables table named after the bridge with the
+// equivalent of
+//
+//	chain postrouting { type nat hook postrouting priority srcnat
+//		ip saddr <subnet> oifname != <bridge> masquerade }
+//	chain input { type filter hook input priority filter
+//		iifname <bridge> ct state established,related accept
+//		iifname <bridge> ip daddr <dns> udp/tcp dport 53 accept ...
+//		iifname <bridge> drop }
+//	chain forward { type filter hook forward priority filter
+//		iifname <bridge> ip daddr <dns> udp/tcp dport 53 accept ...
+//		iifname <bridge> ip daddr <blocked destination> drop ... }
+//
+// so jobs reach the internet and their resolvers but neither the host nor
+// internal networks.
+func (b *bridgeNetwork) setupRules() error {
+	conn, err := nftables.New()
+	if err != nil {
+		return err
+	}
+	table := conn.AddTable(&nftables.Table{Family: nftables.TableFamilyIPv4, Name: b.name})
+	conn.FlushTable(table)
+
+	ifname := make([]byte, unix.IFNAMSIZ)
+	copy(ifname, b.name)
+	fromBridge := []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ifname},
+	}
+	rule := func(chain *nftables.Chain, exprs ...expr.Any) {
+		conn.AddRule(&nftables.Rule{Table: table, Chain: chain, Exprs: exprs})
+	}
+	// ipv4Match loads an IPv4 address of the header at offset and compares
+	// it with prefix
+	ipv4Match := func(offset uint32, prefix netip.Prefix) []expr.Any {
+		addr := prefix.Masked().Addr().As4()
+		return []expr.Any{
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: 4},
+			&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: net.CIDRMask(prefix.Bits(), 32), Xor: make([]byte, 4)},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: addr[:]},
+		}
+	}
+	drop := &expr.Verdict{Kind: expr.VerdictDrop}
+	accept := &expr.Verdict{Kind: expr.VerdictAccept}
+	// acceptDNS lets queries to the bridge's resolvers through, whether
+	// they are on the host or behind it
+	acceptDNS := func(chain *nftables.Chain) {
+		for _, server := range b.dns {
+			for _, proto := range []byte{unix.IPPROTO_UDP, unix.IPPROTO_TCP} {
+				exprs := append(append(append([]expr.Any{}, fromBridge...), ipv4Match(16, netip.PrefixFrom(server, 32))...),
+					&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+					&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{proto}},
+					&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+					&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.BigEndian.PutUint16(53)},
+					accept,
+				)
+				rule(chain, exprs...)
+			}
+		}
+	}
+
+	postrouting := conn.AddChain(&nftables.Chain{
+		Name:     "postrouting",
+		Table:    table,
+		Type:     nftables.ChainTypeNAT,
+		Hooknum:  nftables.ChainHookPostrouting,
+		Priority: nftables.ChainPriorityNATSource,
+	})
+	rule(postrouting, append(ipv4Match(12, b.subnet),
+		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: ifname},
+		&expr.Masq{},
+	)...)
+
+	input := conn.AddChain(&nftables.Chain{
+		Name:     "input",
+		Table:    table,
+		Type:     nftables.ChainTypeFilter,
+		Hooknum:  nftables.ChainHookInput,
+		Priority: nftables.ChainPriorityFilter,
+	})
+	// Replies to connections the host opened to a job are let through
+	established := binaryutil.NativeEndian.PutUint32(expr.CtStateBitESTABLISHED | expr.CtStateBitRELATED)
+	rule(input, append(fromBridge,
+		&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+		&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: established, Xor: make([]byte, 4)},
+		&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: make([]byte, 4)},
+		accept,
+	)...)
+	acceptDNS(input)
+	rule(input, append(fromBridge, drop)...)
+
+	forward := conn.AddChain(&nftables.Chain{
+		Name:     "forward",
+		Table:    table,
+		Type:     nftables.ChainTypeFilter,
+		Hooknum:  nftables.ChainHookForward,
+		Priority: nftables.ChainPriorityFilter,
+	})
+	acceptDNS(forward)
+	for _, prefix := range blockedDestinations {
+		rule(forward, append(append(fromBridge, ipv4Match(16, prefix)...), drop)...)
+	}
+	return conn.Flush()
+}
+
+// attach allocates an address for a job and creates its veth pair, with
+// the host end enslaved to the bridge. The peer stays in the host's
+// namespace until moveToJob.
+func (b *bridgeNetwork) attach(jobID string) (*vethPair, error) {
+	if err := b.setup(); err != nil {
+		return nil, err
+	}
+	address, err := b.allocate(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	bridge, err := netlink.LinkByName(b.name)
+	if err != nil {
+		b.release(address)
+		return nil, fmt.Errorf("failed to find bridge %s: %v", b.name, err)
+	}
+	hostName, peerName := vethNames(jobID)
+	veth := &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: hostName, MasterIndex: bridge.Attrs().Index},
+		PeerName:  peerName,
+	}
+	if err := netlink.LinkAdd(veth); err != nil {
+		b.release(address)
+		return nil, fmt.Errorf("failed to create veth pair %s: %v", hostName, err)
+	}
+	pair := &vethPair{Host: hostName, Peer: peerName, Address: address}
+	// Isolated bridge ports only forward to the bridge itself, so jobs
+	// cannot reach each other
+	if err := netlink.LinkSetIsolated(veth, true); err != nil {
+		b.detach(pair)
+		return nil, fmt.Errorf("failed to isolate %s: %v", hostName, err)
+	}
+	if err := netlink.LinkSetUp(veth); err != nil {
+		b.detach(pair)
+		return nil, fmt.Errorf("failed to bring up %s: %v", hostName, err)
+	}
+	return pair, nil
+}
+
+// moveToJob moves the peer of a job's veth pair into the network namespace
+// of its init process.
+func (b *bridgeNetwork) moveToJob(pair *vethPair, pid int) error {
+	peer, err := netlink.LinkByName(pair.Peer)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %v", pair.Peer, err)
+	}
+	if err := netlink.LinkSetNsPid(peer, pid); err != nil {
+		return fmt.Errorf("failed to move %s into the job's network namespace: %v", pair.Peer, err)
+	}
+	return nil
+}
+
+// detach removes a job's veth pair and releases its address. The pair is
+// already gone when the job's network namespace was destroyed with it.
+func (b *bridgeNetwork) detach(pair *vethPair) {
+	if link, err := netlink.LinkByName(pair.Host); err == nil {
+		if err := netlink.LinkDel(link); err != nil {
+			logger.Warn("failed to remove veth pair", "link", pair.Host, "error", err)
+		}
+	}
+	b.release(pair.Address)
+}
+
+// setupNetwork configures the network namespace of an isolated job from
+// inside it: loopback is brought up and a bridged job's veth peer becomes
+// eth0 with its address and a default route via the bridge.
+func setupNetwork(config initNetwork) error {
+	if config.Mode == NetworkHost || config.Mode == "" {
+		return nil
+	}
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		return fmt.Errorf("failed to find loopback: %v", err)
+	}
+	if err := netlink.LinkSetUp(lo); err != nil {
+		return fmt.Errorf("failed to bring up loopback: %v", err)
+	}
+	if config.Mode != NetworkBridged {
+		return nil
+	}
+
+	link, err := netlink.LinkByName(config.Link)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %v", config.Link, err)
+	}
+	if err := netlink.LinkSetName(link, "eth0"); err != nil {
+		return fmt.Errorf("failed to rename %s to eth0: %v", config.Link, err)
+	}
+	address, err := netlink.ParseAddr(config.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %v", config.Address, err)
+	}
+	if err := netlink.AddrAdd(link, address); err != nil {
+		return fmt.Errorf("failed to assign %s to eth0: %v", config.Address, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("failed to bring up eth0: %v", err)
+	}
+	route := &netlink.Route{LinkIndex: link.Attrs().Index, Gw: net.ParseIP(config.Gateway)}
+	if err := netlink.RouteAdd(route); err != nil {
+		return fmt.Errorf("failed to add default route via %s: %v", config.Gateway, err)
+	}
+	return nil
+}
+
+func prefixToIPNet(prefix netip.Prefix) *net.IPNet {
+	return &net.IPNet{IP: prefix.Addr().AsSlice(), Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen())}
+}
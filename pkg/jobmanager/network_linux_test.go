@@ -0,0 +1,146 @@
+package jobmanager
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+	"github.com/google/nftables"
+	"github.com/vishvananda/netlink"
+)
+
+// runNetworkJob runs script in an isolated job with the given network mode
+// and returns its output.
+func runNetworkJob(t *testing.T, manager *JobManager, network NetworkMode, script string) (*Job, string) {
+	t.Helper()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", script}, limits.Limits{}, StartOptions{Network: network})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	<-job.Done()
+	stdout, stderr, err := manager.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() error = %v", err)
+	}
+	if status := job.Status(); status.State != StateExited || status.ExitCode != 0 {
+		t.Fatalf("status = %+v, want exited 0 (stderr %q)", status, stderr)
+	}
+	return job, string(stdout)
+}
+
+// interfaces lists the interfaces in /proc/net/dev output.
+func interfaces(dev string) []string {
+	var names []string
+	for _, line := range strings.Split(dev, "\n") {
+		if name, _, ok := strings.Cut(line, ":"); ok && !strings.Contains(name, "|") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	return names
+}
+
+func TestNoneNetworkHasOnlyLoopback(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	_, stdout := runNetworkJob(t, New(WithIsolation(true)), NetworkNone, "cat /proc/net/dev")
+	if got := interfaces(stdout); len(got) != 1 || got[0] != "lo" {
+		t.Fatalf("interfaces = %v, want only lo", got)
+	}
+}
+
+func TestBridgedNetwork(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces and links requires root")
+	}
+	const bridgeName = "sentrytest0"
+	subnet := netip.MustParsePrefix("10.213.0.0/24")
+	// The bridge's own address doubles as the jobs' resolver
+	resolver := netip.MustParseAddr("10.213.0.1")
+	t.Cleanup(func() {
+		os.Remove(filepath.Join(bridgeRuntimeDir, bridgeName+".resolv.conf"))
+		if link, err := netlink.LinkByName(bridgeName); err == nil {
+			netlink.LinkDel(link)
+		}
+		if conn, err := nftables.New(); err == nil {
+			conn.DelTable(&nftables.Table{Family: nftables.TableFamilyIPv4, Name: bridgeName})
+			conn.Flush()
+		}
+	})
+
+	manager := New(WithIsolation(true), WithBridge(bridgeName, subnet), WithBridgeDNS([]netip.Addr{resolver}), WithRootfs(RootfsConfig{MountRoots: []string{os.TempDir()}}))
+	job, stdout := runNetworkJob(t, manager, NetworkBridged, "cat /proc/net/dev; echo; cat /proc/net/route")
+	if job.Address != "10.213.0.2/24" {
+		t.Fatalf("job.Address = %q, want 10.213.0.2/24", job.Address)
+	}
+
+	dev, routes, _ := strings.Cut(stdout, "\n\n")
+	if got := strings.Join(interfaces(dev), ","); got != "lo,eth0" && got != "eth0,lo" {
+		t.Fatalf("interfaces = %s, want lo and eth0", got)
+	}
+	// The default route goes through the bridge's address, 10.213.0.1,
+	// which /proc/net/route shows in little-endian hex
+	if !strings.Contains(routes, "eth0\t00000000\t0100D50A") {
+		t.Fatalf("routes = %q, want a default route via 10.213.0.1", routes)
+	}
+
+	bridge, err := netlink.LinkByName(bridgeName)
+	if err != nil {
+		t.Fatalf("bridge was not created: %v", err)
+	}
+	addrs, err := netlink.AddrList(bridge, netlink.FAMILY_V4)
+	if err != nil || len(addrs) != 1 || addrs[0].IPNet.String() != "10.213.0.1/24" {
+		t.Fatalf("bridge addresses = %v (%v), want 10.213.0.1/24", addrs, err)
+	}
+
+	// The job's veth pair and address are released when it exits
+	hostName, _ := vethNames(job.ID)
+	if _, err := netlink.LinkByName(hostName); err == nil {
+		t.Fatalf("veth %s still exists after the job exited", hostName)
+	}
+	// The host, here a listener on the bridge's address, is out of reach
+	listener, err := net.Listen("tcp", "10.213.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+	script := fmt.Sprintf("timeout 1 bash -c 'echo > /dev/tcp/10.213.0.1/%d' 2>/dev/null && echo reached || echo blocked", port)
+	if _, stdout := runNetworkJob(t, manager, NetworkBridged, script); stdout != "blocked\n" {
+		t.Fatalf("connecting to the host printed %q, want blocked", stdout)
+	}
+
+	// Except for DNS to the configured resolvers, which jobs find in their
+	// resolv.conf
+	dns, err := net.Listen("tcp", "10.213.0.1:53")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dns.Close()
+	script = "cat /etc/resolv.conf; timeout 1 bash -c 'echo > /dev/tcp/10.213.0.1/53' 2>/dev/null && echo reached || echo blocked"
+	if _, stdout := runNetworkJob(t, manager, NetworkBridged, script); stdout != "nameserver 10.213.0.1\nreached\n" {
+		t.Fatalf("querying the resolver printed %q, want its resolv.conf and reached", stdout)
+	}
+	rootfsJob, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "cat /etc/resolv.conf"}, limits.Limits{}, StartOptions{Network: NetworkBridged, Mount: t.TempDir()})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	<-rootfsJob.Done()
+	if stdout, stderr, _ := manager.GetJobOutput(rootfsJob.ID); string(stdout) != "nameserver 10.213.0.1\n" {
+		t.Fatalf("resolv.conf in the job's root = %q (stderr %q), want the resolver", stdout, stderr)
+	}
+	if data, err := os.ReadFile("/etc/resolv.conf"); err != nil || strings.Contains(string(data), "10.213.0.1") {
+		t.Fatalf("host resolv.conf = (%q, %v), want it untouched", data, err)
+	}
+
+	manager.bridge.mu.Lock()
+	defer manager.bridge.mu.Unlock()
+	if len(manager.bridge.inUse) != 0 {
+		t.Fatalf("addresses in use = %v, want none", manager.bridge.inUse)
+	}
+}
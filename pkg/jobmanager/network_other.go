@@ -0,0 +1,19 @@
+//go:build !linux
+
+package jobmanager
+
+import "errors"
+
+var errNetworkNamespaces = errors.New("network modes require Linux network namespaces")
+
+func (b *bridgeNetwork) attach(jobID string) (*vethPair, error) {
+	return nil, errNetworkNamespaces
+}
+
+func (b *bridgeNetwork) moveToJob(pair *vethPair, pid int) error {
+	return errNetworkNamespaces
+}
+
+func (b *bridgeNetwork) detach(pair *vethPair) {
+	b.release(pair.Address)
+}
@@ -0,0 +1,113 @@
+package jobmanager
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+func TestParseNetworkMode(t *testing.T) {
+	for in, want := range map[string]NetworkMode{"": NetworkHost, "host": NetworkHost, "none": NetworkNone, "bridged": NetworkBridged} {
+		got, err := ParseNetworkMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseNetworkMode(%q) = (%q, %v), want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseNetworkMode("overlay"); err == nil {
+		t.Error("ParseNetworkMode(overlay) succeeded, want error")
+	}
+}
+
+func TestNewBridgeNetworkValidates(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		subnet string
+	}{
+		{"sentry0", "fd00::/64"},
+		{"sentry0", "10.0.0.0/31"},
+		{"", "10.0.0.0/24"},
+		{"a-very-long-bridge", "10.0.0.0/24"},
+	} {
+		if _, err := newBridgeNetwork(tt.name, netip.MustParsePrefix(tt.subnet), nil); err == nil {
+			t.Errorf("newBridgeNetwork(%q, %s) succeeded, want error", tt.name, tt.subnet)
+		}
+	}
+	for _, server := range []string{"127.0.0.53", "0.0.0.0", "2001:db8::53"} {
+		if _, err := newBridgeNetwork("sentry0", DefaultBridgeSubnet, []netip.Addr{netip.MustParseAddr(server)}); err == nil {
+			t.Errorf("newBridgeNetwork() with DNS server %s succeeded, want error", server)
+		}
+	}
+}
+
+func TestParseNameservers(t *testing.T) {
+	data := []byte("# generated\nnameserver 127.0.0.53\nnameserver 10.0.0.2\nsearch example.com\nnameserver fd00::1\nnameserver 1.1.1.1 # public\noptions edns0\n")
+	got := parseNameservers(data)
+	want := []netip.Addr{netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("1.1.1.1")}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("parseNameservers() = %v, want %v", got, want)
+	}
+
+	bridge, err := newBridgeNetwork("sentry0", DefaultBridgeSubnet, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(bridge.resolvConfData()); got != "nameserver 10.0.0.2\nnameserver 1.1.1.1\n" {
+		t.Fatalf("resolv.conf = %q", got)
+	}
+	if bridge.resolvConf != "/run/sentry-run/sentry0.resolv.conf" {
+		t.Fatalf("resolvConf = %q", bridge.resolvConf)
+	}
+}
+
+func TestBridgeAllocatesAddresses(t *testing.T) {
+	bridge, err := newBridgeNetwork("sentry0", netip.MustParsePrefix("10.88.0.7/29"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bridge.gateway != netip.MustParseAddr("10.88.0.1") {
+		t.Fatalf("gateway = %s, want 10.88.0.1", bridge.gateway)
+	}
+
+	// .0 is the network, .1 the gateway and .7 the broadcast address
+	var got []netip.Prefix
+	for i := 0; i < 5; i++ {
+		address, err := bridge.allocate("job")
+		if err != nil {
+			t.Fatalf("allocate() #%d error = %v", i, err)
+		}
+		got = append(got, address)
+	}
+	for i, address := range got {
+		if want := netip.PrefixFrom(netip.AddrFrom4([4]byte{10, 88, 0, byte(i + 2)}), 29); address != want {
+			t.Errorf("address #%d = %s, want %s", i, address, want)
+		}
+	}
+	if _, err := bridge.allocate("job"); err == nil {
+		t.Fatal("allocate() on a full subnet succeeded, want error")
+	}
+
+	// Released addresses are reused once the search wraps around
+	bridge.release(got[1])
+	if address, err := bridge.allocate("job"); err != nil || address != got[1] {
+		t.Fatalf("allocate() = (%s, %v), want %s", address, err, got[1])
+	}
+
+	bridge.release(got[3])
+	bridge.reserve("recovered", got[3])
+	if _, err := bridge.allocate("job"); err == nil {
+		t.Fatal("allocate() handed out a reserved address")
+	}
+}
+
+func TestStartJobNetworkRequiresIsolation(t *testing.T) {
+	manager := New()
+	for _, mode := range []NetworkMode{NetworkNone, NetworkBridged, "overlay"} {
+		if _, err := manager.StartJob("alice", "/bin/true", nil, limits.Limits{}, StartOptions{Network: mode}); err == nil {
+			t.Errorf("StartJob(network %s) without isolation succeeded, want error", mode)
+		}
+	}
+	if jobs := manager.ListJobs(); len(jobs) != 0 {
+		t.Fatalf("ListJobs() = %d jobs, want none", len(jobs))
+	}
+}
@@ -0,0 +1,74 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+// memoryEventsPollInterval is how often memory.events is read where it
+// cannot be watched with inotify.
+const memoryEventsPollInterval = time.Second
+
+// watchMemoryEvents records the job's OOM counters whenever the kernel
+// updates memory.events in its cgroup, until stop is closed. The cgroup v2
+// memory.events file generates a modify event on every change.
+func watchMemoryEvents(job *Job, cgroupPath string, stop <-chan struct{}) {
+	path := filepath.Join(cgroupPath, "memory.events")
+	if _, err := os.Stat(path); err != nil {
+		// The memory controller is not enabled for the job's cgroup
+		return
+	}
+
+	record := func() { job.recordMemoryEvents(cgroupPath) }
+	err := watchFile(path, stop, record)
+	if err == nil {
+		return
+	}
+	logger.Debug("cannot watch memory events, polling instead", "job_id", job.ID, "error", err)
+
+	ticker := time.NewTicker(memoryEventsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			record()
+		}
+	}
+}
+
+// recordMemoryEvents reads the oom and oom_kill counters of the job's cgroup
+// into its status and logs when they grow.
+func (job *Job) recordMemoryEvents(cgroupPath string) {
+	events, err := readFlatKeyedFile(filepath.Join(cgroupPath, "memory.events"))
+	if err != nil {
+		return
+	}
+	oom, oomKills := events["oom"], events["oom_kill"]
+
+	job.mu.Lock()
+	prevOOM, prevKills := job.status.OOMEvents, job.status.OOMKills
+	job.status.OOMEvents, job.status.OOMKills = oom, oomKills
+	memoryMax := job.Limits.MemoryMax
+	job.mu.Unlock()
+
+	switch {
+	case oomKills > prevKills:
+		logger.Warn("job process killed by the OOM killer", "job_id", job.ID, "oom_kills", oomKills, "memory_max", limits.FormatValue(memoryMax))
+	case oom > prevOOM:
+		logger.Warn("job reached its memory limit", "job_id", job.ID, "oom_events", oom, "memory_max", limits.FormatValue(memoryMax))
+	}
+}
+
+// oomError describes an OOM-killed job for Status.Error.
+func oomError(oomKills, memoryMax uint64) string {
+	if memoryMax == 0 || memoryMax == limits.Max {
+		return fmt.Sprintf("%d process(es) killed by the OOM killer", oomKills)
+	}
+	return fmt.Sprintf("%d process(es) killed by the OOM killer after exceeding the memory limit of %d bytes", oomKills, memoryMax)
+}
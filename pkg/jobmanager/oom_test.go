@@ -0,0 +1,83 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+func writeMemoryEvents(t *testing.T, root string, oom, oomKill int) {
+	t.Helper()
+	events := fmt.Sprintf("low 0\nhigh 0\nmax 0\noom %d\noom_kill %d\n", oom, oomKill)
+	if err := os.WriteFile(filepath.Join(root, "sentry-run-job-1", "memory.events"), []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchMemoryEvents(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+	writeMemoryEvents(t, root, 0, 0)
+
+	job := &Job{ID: "job-1", status: Status{State: StateRunning}, done: make(chan struct{})}
+	stopped := make(chan struct{})
+	go func() {
+		watchMemoryEvents(job, filepath.Join(root, "sentry-run-job-1"), job.done)
+		close(stopped)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status().OOMKills != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("status = %+v, want the OOM kill recorded", job.Status())
+		}
+		// Rewrite until the watcher, which may not be set up yet, sees it
+		writeMemoryEvents(t, root, 2, 1)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := job.Status(); status.OOMEvents != 2 {
+		t.Fatalf("OOMEvents = %d, want 2", status.OOMEvents)
+	}
+
+	close(job.done)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop after the job finished")
+	}
+}
+
+func TestMarkFinishedDetectsOOMKill(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+
+	tests := []struct {
+		name    string
+		script  string
+		oomKill int
+		want    State
+	}{
+		{"job process killed", "kill -9 $$", 1, StateOOMKilled},
+		{"child killed, job failed", "exit 137", 1, StateOOMKilled},
+		{"child killed, job recovered", "exit 0", 1, StateExited},
+		{"killed without OOM", "kill -9 $$", 0, StateKilled},
+	}
+	for _, tt := range tests {
+		writeMemoryEvents(t, root, tt.oomKill, tt.oomKill)
+		cmd := exec.Command("/bin/sh", "-c", tt.script)
+		job := &Job{ID: "job-1", Cmd: cmd, Limits: limits.Limits{MemoryMax: 1 << 20}, status: Status{State: StateRunning}}
+		job.markFinished(cmd.Run())
+
+		status := job.Status()
+		if status.State != tt.want || status.OOMKills != uint64(tt.oomKill) {
+			t.Errorf("%s: status = %+v, want %s", tt.name, status, tt.want)
+		}
+		if tt.want == StateOOMKilled && !strings.Contains(status.Error, "1048576 bytes") {
+			t.Errorf("%s: error = %q, want the memory limit", tt.name, status.Error)
+		}
+	}
+}
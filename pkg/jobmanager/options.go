@@ -0,0 +1,147 @@
+package jobmanager
+
+import (
+	"net/netip"
+	"time"
+)
+
+// DefaultStopGracePeriod is how long StopJob waits after SIGTERM before
+// escalating to SIGKILL when no grace period is configured.
+const DefaultStopGracePeriod = 10 * time.Second
+
+// DefaultHistoryLimit is the number of bytes of the most recent output kept
+// in memory for each stream of a job.
+const DefaultHistoryLimit = 4 << 20
+
+// Option configures a JobManager.
+type Option func(*JobManager)
+
+// WithStopGracePeriod sets how long StopJob waits for a job to exit after
+// SIGTERM before escalating to SIGKILL.
+func WithStopGracePeriod(d time.Duration) Option {
+	return func(m *JobManager) {
+		if d > 0 {
+			m.stopGracePeriod = d
+		}
+	}
+}
+
+// WithHistoryLimit sets how many bytes of the most recent stdout and stderr
+// are kept in memory per job. Zero or less keeps the whole output.
+func WithHistoryLimit(n int) Option {
+	return func(m *JobManager) {
+		m.historyLimit = n
+	}
+}
+
+// WithSpillDir makes jobs append output evicted from the in-memory history
+// to per-job files in dir instead of discarding it.
+func WithSpillDir(dir string) Option {
+	return func(m *JobManager) {
+		m.spillDir = dir
+	}
+}
+
+// WithLogStore persists job output to store so that logs of jobs from
+// previous server runs remain available.
+func WithLogStore(store *LogStore) Option {
+	return func(m *JobManager) {
+		m.logStore = store
+	}
+}
+
+// WithRegistry persists job metadata to registry so that Recover can
+// restore jobs after a restart.
+func WithRegistry(registry *Registry) Option {
+	return func(m *JobManager) {
+		m.registry = registry
+	}
+}
+
+// WithCgroupGCInterval sets how often RunCgroupGC collects orphaned cgroups.
+// Zero or less only collects them once.
+func WithCgroupGCInterval(d time.Duration) Option {
+	return func(m *JobManager) {
+		m.cgroupGCInterval = d
+	}
+}
+
+// WithCgroupGCDryRun makes the orphaned cgroup collector only report the
+// cgroups it would remove.
+func WithCgroupGCDryRun(dryRun bool) Option {
+	return func(m *JobManager) {
+		m.cgroupGCDryRun = dryRun
+	}
+}
+
+// WithDefaultPidsLimit sets the pids.max applied to jobs that do not set a
+// PID limit themselves, protecting the host from fork bombs. Zero leaves
+// such jobs unlimited.
+func WithDefaultPidsLimit(n uint64) Option {
+	return func(m *JobManager) {
+		m.defaultPidsLimit = n
+	}
+}
+
+// WithPressureTrigger makes jobs with a cgroup report a stall event whenever
+// their tasks are stalled on CPU, memory or IO for longer than the trigger's
+// threshold within its window. The trigger must pass Validate.
+func WithPressureTrigger(trigger PressureTrigger) Option {
+	return func(m *JobManager) {
+		m.pressureTrigger = &trigger
+	}
+}
+
+// WithIsolation starts jobs in new PID, mount, UTS and IPC namespaces with
+// their own /proc, so they run as PID 1 and cannot see or signal other
+// jobs or the server. The server binary is re-executed as each job's init,
+// so it must be able to run /proc/self/exe.
+func WithIsolation(enabled bool) Option {
+	return func(m *JobManager) {
+		m.isolate = enabled
+	}
+}
+
+// WithRootfs sets how the root filesystems of isolated jobs are assembled.
+// Nil binds keep DefaultRootfsBinds and empty layers DefaultRootfsLayers.
+func WithRootfs(config RootfsConfig) Option {
+	return func(m *JobManager) {
+		if config.Binds == nil {
+			config.Binds = DefaultRootfsBinds
+		}
+		if config.Layers == "" {
+			config.Layers = DefaultRootfsLayers
+		}
+		m.rootfs = config
+	}
+}
+
+// WithProtectedPaths adds host directories, such as the server's data and
+// certificate directories, that jobs must not mount and that isolated jobs
+// without a root filesystem of their own do not see.
+func WithProtectedPaths(paths ...string) Option {
+	return func(m *JobManager) {
+		m.protected = append(m.protected, paths...)
+	}
+}
+
+// WithBridgeDNS sets the resolvers bridged jobs may query and find in their
+// /etc/resolv.conf. By default they are the non-loopback IPv4 nameservers
+// of the host's resolv.conf, or of systemd-resolved's upstream servers. An
+// empty list leaves jobs with the host's resolv.conf and no DNS exception
+// in the bridge's filter rules.
+func WithBridgeDNS(servers []netip.Addr) Option {
+	return func(m *JobManager) {
+		m.bridgeDNS = append([]netip.Addr{}, servers...)
+	}
+}
+
+// WithBridge sets the bridge bridged jobs are attached to and the IPv4
+// subnet their addresses are allocated from. The first address of the
+// subnet is the bridge's.
+func WithBridge(name string, subnet netip.Prefix) Option {
+	return func(m *JobManager) {
+		m.bridgeName = name
+		m.bridgeSubnet = subnet
+	}
+}
@@ -0,0 +1,219 @@
+package jobmanager
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pressure resources, named after their cgroup interface files.
+const (
+	PressureCPU    = "cpu"
+	PressureMemory = "memory"
+	PressureIO     = "io"
+)
+
+var pressureResources = []string{PressureCPU, PressureMemory, PressureIO}
+
+// PressureLine is one line of a pressure file: the share of wall time in
+// which tasks were stalled, averaged over 10, 60 and 300 seconds in
+// percent, and the total stall time.
+type PressureLine struct {
+	Avg10     float64
+	Avg60     float64
+	Avg300    float64
+	TotalUsec uint64
+}
+
+// Pressure is the pressure stall information of one resource. Some counts
+// time in which at least one task was stalled, Full time in which all were.
+type Pressure struct {
+	Some PressureLine
+	Full PressureLine
+}
+
+// JobPressure is the pressure stall information of a job's cgroup.
+type JobPressure struct {
+	CPU    Pressure
+	Memory Pressure
+	IO     Pressure
+}
+
+// Stalls counts the pressure trigger events of a job by resource.
+type Stalls struct {
+	CPU    uint64 `json:"cpu,omitempty"`
+	Memory uint64 `json:"memory,omitempty"`
+	IO     uint64 `json:"io,omitempty"`
+}
+
+// PressureTrigger reports a job as stalled when its tasks were stalled on a
+// resource for more than Threshold within any Window.
+type PressureTrigger struct {
+	Threshold time.Duration
+	Window    time.Duration
+}
+
+// ParsePressureTrigger parses a trigger written as "threshold/window", for
+// example "100ms/1s".
+func ParsePressureTrigger(s string) (PressureTrigger, error) {
+	threshold, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return PressureTrigger{}, fmt.Errorf("invalid pressure trigger %q, want threshold/window such as 100ms/1s", s)
+	}
+	var trigger PressureTrigger
+	var err error
+	if trigger.Threshold, err = time.ParseDuration(threshold); err != nil {
+		return PressureTrigger{}, fmt.Errorf("invalid pressure threshold: %v", err)
+	}
+	if trigger.Window, err = time.ParseDuration(window); err != nil {
+		return PressureTrigger{}, fmt.Errorf("invalid pressure window: %v", err)
+	}
+	return trigger, trigger.Validate()
+}
+
+// Validate checks the trigger against the kernel's limits.
+func (t PressureTrigger) Validate() error {
+	if t.Window < 500*time.Millisecond || t.Window > 10*time.Second {
+		return fmt.Errorf("pressure window %s must be between 500ms and 10s", t.Window)
+	}
+	if t.Threshold <= 0 || t.Threshold > t.Window {
+		return fmt.Errorf("pressure threshold %s must be positive and at most the window %s", t.Threshold, t.Window)
+	}
+	return nil
+}
+
+// String renders the trigger as written to a pressure file.
+func (t PressureTrigger) String() string {
+	return fmt.Sprintf("some %d %d", t.Threshold.Microseconds(), t.Window.Microseconds())
+}
+
+// GetJobPressure reads the pressure stall information of a running job.
+// Resources whose pressure file is missing are reported as zero.
+func (m *JobManager) GetJobPressure(jobID string) (JobPressure, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return JobPressure{}, err
+	}
+	if job.Status().State.Finished() {
+		return JobPressure{}, fmt.Errorf("job %s is not running", jobID)
+	}
+	if _, err := os.Stat(getCgroupPath(jobID)); err != nil {
+		return JobPressure{}, fmt.Errorf("job %s was started without a cgroup, it has no pressure information", jobID)
+	}
+	return readJobPressure(jobID)
+}
+
+func readJobPressure(jobID string) (JobPressure, error) {
+	var pressure JobPressure
+	for _, resource := range pressureResources {
+		p, err := readPressure(jobID, resource)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return JobPressure{}, err
+		}
+		switch resource {
+		case PressureCPU:
+			pressure.CPU = p
+		case PressureMemory:
+			pressure.Memory = p
+		case PressureIO:
+			pressure.IO = p
+		}
+	}
+	return pressure, nil
+}
+
+// readPressure parses a pressure file such as cpu.pressure:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressure(jobID, resource string) (Pressure, error) {
+	name := resource + ".pressure"
+	data, err := os.ReadFile(filepath.Join(getCgroupPath(jobID), name))
+	if err != nil {
+		return Pressure{}, err
+	}
+
+	var pressure Pressure
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var line *PressureLine
+		switch fields[0] {
+		case "some":
+			line = &pressure.Some
+		case "full":
+			line = &pressure.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			if key == "total" {
+				line.TotalUsec, err = strconv.ParseUint(value, 10, 64)
+			} else {
+				var avg float64
+				avg, err = strconv.ParseFloat(value, 64)
+				switch key {
+				case "avg10":
+					line.Avg10 = avg
+				case "avg60":
+					line.Avg60 = avg
+				case "avg300":
+					line.Avg300 = avg
+				}
+			}
+			if err != nil {
+				return Pressure{}, fmt.Errorf("failed to parse %s: %v", name, err)
+			}
+		}
+	}
+	return pressure, nil
+}
+
+// watchPressure reports the job as stalled whenever the trigger fires on
+// one of the resources of its cgroup, until stop is closed.
+func (m *JobManager) watchPressure(job *Job, cgroupPath string, trigger PressureTrigger, stop <-chan struct{}) {
+	for _, resource := range pressureResources {
+		path := filepath.Join(cgroupPath, resource+".pressure")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		m.goWatch(func() {
+			err := watchPressureTrigger(path, trigger, stop, func() { job.recordStall(resource, trigger) })
+			if err != nil {
+				logger.Warn("failed to watch job pressure", "job_id", job.ID, "resource", resource, "error", err)
+			}
+		})
+	}
+}
+
+// recordStall counts a trigger event and logs it with the current pressure.
+func (job *Job) recordStall(resource string, trigger PressureTrigger) {
+	job.mu.Lock()
+	var count uint64
+	switch resource {
+	case PressureCPU:
+		job.status.Stalls.CPU++
+		count = job.status.Stalls.CPU
+	case PressureMemory:
+		job.status.Stalls.Memory++
+		count = job.status.Stalls.Memory
+	case PressureIO:
+		job.status.Stalls.IO++
+		count = job.status.Stalls.IO
+	}
+	job.mu.Unlock()
+
+	pressure, _ := readPressure(job.ID, resource)
+	logger.Warn("job stalled", "job_id", job.ID, "resource", resource, "threshold", trigger.Threshold, "window", trigger.Window,
+		"some_avg10", pressure.Some.Avg10, "full_avg10", pressure.Full.Avg10, "stalls", count)
+}
@@ -0,0 +1,62 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// pressurePollTimeout bounds how long a trigger poll blocks before checking
+// whether the job has finished, in milliseconds.
+const pressurePollTimeout = 1000
+
+// watchPressureTrigger registers trigger on the pressure file at path and
+// calls onStall every time the kernel signals it, until stop is closed or
+// the cgroup is removed.
+func watchPressureTrigger(path string, trigger PressureTrigger, stop <-chan struct{}, onStall func()) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	// The trigger lives as long as the descriptor stays open
+	if _, err := file.Write(append([]byte(trigger.String()), 0)); err != nil {
+		return fmt.Errorf("failed to set pressure trigger %q on %s: %v", trigger, path, err)
+	}
+
+	// Pressure files are not pollable through the runtime poller, so poll
+	// the raw descriptor with a timeout to notice when to stop
+	raw, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		var revents int16
+		var pollErr error
+		if err := raw.Control(func(fd uintptr) {
+			fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLPRI}}
+			_, pollErr = unix.Poll(fds, pressurePollTimeout)
+			revents = fds[0].Revents
+		}); err != nil {
+			return err
+		}
+		switch {
+		case pollErr == unix.EINTR:
+		case pollErr != nil:
+			return fmt.Errorf("failed to poll %s: %v", path, pollErr)
+		case revents&unix.POLLERR != 0:
+			// The cgroup was removed
+			return nil
+		case revents&unix.POLLPRI != 0:
+			onStall()
+		}
+	}
+}
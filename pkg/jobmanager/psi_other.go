@@ -0,0 +1,10 @@
+//go:build !linux
+
+package jobmanager
+
+import "errors"
+
+// watchPressureTrigger reports that pressure triggers need Linux.
+func watchPressureTrigger(path string, trigger PressureTrigger, stop <-chan struct{}, onStall func()) error {
+	return errors.New("pressure triggers require Linux")
+}
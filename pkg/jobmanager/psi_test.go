@@ -0,0 +1,112 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetJobPressure(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+	files := map[string]string{
+		"cpu.pressure":    "some avg10=0.56 avg60=1.78 avg300=2.14 total=71473629\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+		"memory.pressure": "some avg10=12.50 avg60=4.00 avg300=1.00 total=2500000\nfull avg10=10.00 avg60=3.00 avg300=0.50 total=2000000\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, "sentry-run-job-1", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := New()
+	manager.jobs.Store("job-1", &Job{ID: "job-1", status: Status{State: StateRunning}, done: make(chan struct{})})
+
+	pressure, err := manager.GetJobPressure("job-1")
+	if err != nil {
+		t.Fatalf("GetJobPressure() error = %v", err)
+	}
+	if want := (PressureLine{Avg10: 0.56, Avg60: 1.78, Avg300: 2.14, TotalUsec: 71473629}); pressure.CPU.Some != want {
+		t.Errorf("CPU.Some = %+v, want %+v", pressure.CPU.Some, want)
+	}
+	if want := (Pressure{
+		Some: PressureLine{Avg10: 12.5, Avg60: 4, Avg300: 1, TotalUsec: 2500000},
+		Full: PressureLine{Avg10: 10, Avg60: 3, Avg300: 0.5, TotalUsec: 2000000},
+	}); pressure.Memory != want {
+		t.Errorf("Memory = %+v, want %+v", pressure.Memory, want)
+	}
+	// io.pressure is missing, as when the io controller is not enabled
+	if pressure.IO != (Pressure{}) {
+		t.Errorf("IO = %+v, want zero", pressure.IO)
+	}
+
+	stats, err := manager.GetJobStats("job-1")
+	if err != nil {
+		t.Fatalf("GetJobStats() error = %v", err)
+	}
+	if stats.Pressure != pressure {
+		t.Errorf("stats.Pressure = %+v, want %+v", stats.Pressure, pressure)
+	}
+}
+
+func TestGetJobPressureInvalid(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+	if err := os.WriteFile(filepath.Join(root, "sentry-run-job-1", "io.pressure"), []byte("some avg10=x total=0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := New()
+	manager.jobs.Store("job-1", &Job{ID: "job-1", status: Status{State: StateRunning}, done: make(chan struct{})})
+	manager.jobs.Store("exited", &Job{ID: "exited", status: Status{State: StateExited}, done: make(chan struct{})})
+
+	for _, jobID := range []string{"job-1", "exited", "missing"} {
+		if _, err := manager.GetJobPressure(jobID); err == nil {
+			t.Errorf("GetJobPressure(%q) succeeded, want error", jobID)
+		}
+	}
+}
+
+func TestParsePressureTrigger(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    PressureTrigger
+		wantErr bool
+	}{
+		{in: "100ms/1s", want: PressureTrigger{Threshold: 100 * time.Millisecond, Window: time.Second}},
+		{in: "2s/10s", want: PressureTrigger{Threshold: 2 * time.Second, Window: 10 * time.Second}},
+		{in: "100ms", wantErr: true},
+		{in: "x/1s", wantErr: true},
+		{in: "100ms/y", wantErr: true},
+		{in: "10ms/100ms", wantErr: true},
+		{in: "1s/11s", wantErr: true},
+		{in: "2s/1s", wantErr: true},
+		{in: "0s/1s", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePressureTrigger(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePressureTrigger(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParsePressureTrigger(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	if got, want := (PressureTrigger{Threshold: 150 * time.Millisecond, Window: time.Second}).String(), "some 150000 1000000"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRecordStall(t *testing.T) {
+	fakeCgroupRoot(t, "sentry-run-job-1")
+	job := &Job{ID: "job-1", status: Status{State: StateRunning}, done: make(chan struct{})}
+	trigger := PressureTrigger{Threshold: 100 * time.Millisecond, Window: time.Second}
+
+	job.recordStall(PressureMemory, trigger)
+	job.recordStall(PressureMemory, trigger)
+	job.recordStall(PressureIO, trigger)
+
+	if got, want := job.Status().Stalls, (Stalls{Memory: 2, IO: 1}); got != want {
+		t.Errorf("Stalls = %+v, want %+v", got, want)
+	}
+}
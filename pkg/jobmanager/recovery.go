@@ -0,0 +1,225 @@
+package jobmanager
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// adoptedPollInterval is how often re-adopted jobs are checked for exit,
+// since they are not children of the server and cannot be waited on.
+const adoptedPollInterval = 500 * time.Millisecond
+
+// Recover loads the jobs of previous server runs from the registry. Jobs
+// that were running are re-adopted when their processes are still alive,
+// so they can be listed, waited on and killed, and are marked lost
+// otherwise. It must be called before any job is started.
+func (m *JobManager) Recover() error {
+	if m.registry == nil {
+		return nil
+	}
+
+	records, err := m.registry.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		job := recoveredJob(record)
+		m.jobs.Store(job.ID, job)
+
+		switch {
+		case record.Status.State.Finished():
+			close(job.done)
+		case processesAlive(record):
+			logger.Info("re-adopting job", "job_id", job.ID, "pid", job.PID)
+			m.reserveAddress(job)
+			if !job.Limits.IsZero() {
+				m.watchCgroup(job)
+			}
+			drained := m.adoptOutput(job)
+			cgroupPath := getCgroupPath(job.ID)
+			m.goWatch(func() { m.watchAdopted(job, cgroupPath, drained) })
+		default:
+			logger.Warn("job lost while the server was down", "job_id", job.ID, "pid", job.PID)
+			job.markLost()
+			if err := cleanupCgroup(job); err != nil {
+				logger.Warn("failed to cleanup cgroup", "job_id", job.ID, "error", err)
+			}
+			m.saveRecord(job)
+		}
+	}
+	return nil
+}
+
+func recoveredJob(record jobRecord) *Job {
+	return &Job{
+		ID:           record.ID,
+		PID:          record.PID,
+		Owner:        record.Owner,
+		Command:      record.Command,
+		Args:         record.Args,
+		Limits:       record.Limits,
+		Mount:        record.Mount,
+		Isolated:     record.Isolated,
+		Network:      record.Network,
+		Address:      record.Address,
+		Image:        record.Image,
+		LayerDir:     record.LayerDir,
+		KeepRootfs:   record.KeepRootfs,
+		DeviceId:     record.DeviceId,
+		limitChanges: record.LimitChanges,
+		status:       record.Status,
+		done:         make(chan struct{}),
+		recovered:    true,
+	}
+}
+
+// reserveAddress keeps the bridge address of a re-adopted job from being
+// handed out again while it runs. Its veth pair goes away with its network
+// namespace, so only the address is tracked.
+func (m *JobManager) reserveAddress(job *Job) {
+	if job.Network != NetworkBridged || m.bridge == nil {
+		return
+	}
+	address, err := netip.ParsePrefix(job.Address)
+	if err != nil {
+		logger.Warn("re-adopted bridged job has no valid address", "job_id", job.ID, "address", job.Address)
+		return
+	}
+	hostName, peerName := vethNames(job.ID)
+	m.bridge.reserve(job.ID, address)
+	job.veth = &vethPair{Host: hostName, Peer: peerName, Address: address}
+	job.bridge = m.bridge
+}
+
+// processesAlive reports whether any process of a recorded job still runs:
+// a member of its cgroup or, without one, its process group leader.
+func processesAlive(record jobRecord) bool {
+	if data, err := os.ReadFile(filepath.Join(record.CgroupPath, "cgroup.procs")); err == nil {
+		return len(bytes.TrimSpace(data)) > 0
+	}
+	if record.PID <= 0 {
+		return false
+	}
+	// Jobs lead their own process group, which guards against PID reuse
+	pgid, err := syscall.Getpgid(record.PID)
+	return err == nil && pgid == record.PID
+}
+
+// adoptOutput reopens the log and output pipes of a re-adopted job and
+// persists what the job writes from now on, including what it wrote while
+// no server was reading. It returns a channel closed once both pipes are
+// drained, or nil if the job's output cannot be read.
+func (m *JobManager) adoptOutput(job *Job) <-chan struct{} {
+	if m.logStore == nil {
+		return nil
+	}
+	log, err := m.logStore.Reopen(job.ID)
+	if err != nil {
+		logger.Warn("failed to reopen job log, output will not be persisted", "job_id", job.ID, "error", err)
+		return nil
+	}
+	stdout, err := log.openPipe(false)
+	if err != nil {
+		logger.Warn("failed to reopen job output, output will not be persisted", "job_id", job.ID, "error", err)
+		log.Close()
+		return nil
+	}
+	stderr, err := log.openPipe(true)
+	if err != nil {
+		logger.Warn("failed to reopen job output, output will not be persisted", "job_id", job.ID, "error", err)
+		stdout.Close()
+		log.Close()
+		return nil
+	}
+
+	job.mu.Lock()
+	job.log = log
+	job.Stdout = stdout
+	job.Stderr = stderr
+	job.mu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go job.persistOutput(&wg, stdout, false)
+	go job.persistOutput(&wg, stderr, true)
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	return drained
+}
+
+// persistOutput appends one output stream of a re-adopted job to its log.
+// Jobs of previous server runs have no in-memory history or subscribers.
+func (job *Job) persistOutput(wg *sync.WaitGroup, reader io.Reader, isStderr bool) {
+	defer wg.Done()
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			job.mu.Lock()
+			if job.log != nil {
+				if err := job.log.Write(buffer[:n], isStderr); err != nil {
+					logger.Warn("failed to persist job output", "job_id", job.ID, "error", err)
+				}
+			}
+			job.mu.Unlock()
+		}
+		if err == io.EOF || errors.Is(err, os.ErrClosed) {
+			return
+		}
+		if err != nil {
+			logger.Warn("failed to read job output", "job_id", job.ID, "pid", job.PID, "error", err)
+			return
+		}
+	}
+}
+
+// watchAdopted takes over the owner role of run for a re-adopted job, until
+// the job finishes or the manager is closed. drained, if not nil, is closed
+// once the job's output has been drained.
+func (m *JobManager) watchAdopted(job *Job, cgroupPath string, drained <-chan struct{}) {
+	record := jobRecord{PID: job.PID, CgroupPath: cgroupPath}
+	ticker := time.NewTicker(adoptedPollInterval)
+	defer ticker.Stop()
+	for processesAlive(record) {
+		select {
+		case <-m.closed:
+			return
+		case <-ticker.C:
+		}
+	}
+
+	if drained != nil {
+		// Descendants that outlive the job may keep the pipes open
+		select {
+		case <-drained:
+		case <-time.After(outputDrainTimeout):
+			logger.Warn("job output still open after exit, closing pipes", "job_id", job.ID, "pid", job.PID)
+		}
+		job.Stdout.Close()
+		job.Stderr.Close()
+		<-drained
+		m.closeLog(job)
+	}
+
+	job.markAdoptedFinished(cgroupPath)
+	job.detachNetwork()
+	status := job.Status()
+	logger.Info("job finished", "job_id", job.ID, "pid", job.PID, "state", status.State)
+
+	if err := deleteCgroupDir(cgroupPath); err != nil {
+		logger.Warn("failed to cleanup cgroup", "job_id", job.ID, "pid", job.PID, "error", err)
+	}
+	m.saveRecord(job)
+	close(job.done)
+}
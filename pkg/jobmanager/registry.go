@@ -0,0 +1,123 @@
+package jobmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+const recordExt = ".json"
+
+// Registry persists the metadata of every job under a data directory so
+// that the job manager can recover its jobs after a restart. Each job is
+// stored in its own file, replaced atomically on every state change:
+//
+//	<dir>/<job_id>.json
+type Registry struct {
+	dir string
+}
+
+// NewRegistry creates a registry rooted at dir.
+func NewRegistry(dir string) (*Registry, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create registry directory %s: %v", dir, err)
+	}
+	return &Registry{dir: dir}, nil
+}
+
+// jobRecord is the persisted description of a job.
+type jobRecord struct {
+	ID           string        `json:"id"`
+	Owner        string        `json:"owner"`
+	Command      string        `json:"command"`
+	Args         []string      `json:"args,omitempty"`
+	Limits       limits.Limits `json:"limits"`
+	Mount        string        `json:"mount,omitempty"`
+	Isolated     bool          `json:"isolated,omitempty"`
+	Network      NetworkMode   `json:"network,omitempty"`
+	Address      string        `json:"address,omitempty"`
+	Image        string        `json:"image,omitempty"`
+	LayerDir     string        `json:"layer_dir,omitempty"`
+	KeepRootfs   bool          `json:"keep_rootfs,omitempty"`
+	DeviceId     string        `json:"device_id,omitempty"`
+	PID          int           `json:"pid,omitempty"`
+	CgroupPath   string        `json:"cgroup_path"`
+	Status       Status        `json:"status"`
+	LimitChanges []LimitChange `json:"limit_changes,omitempty"`
+}
+
+func (r *Registry) recordPath(jobID string) string {
+	return filepath.Join(r.dir, jobID+recordExt)
+}
+
+// Save atomically replaces the record of a job. The record is written to
+// a temporary file and synced before it replaces the old one, so a crash
+// leaves either record intact.
+func (r *Registry) Save(record jobRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(r.dir, record.ID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write job record: %v", err)
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), r.recordPath(record.ID))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write job record: %v", err)
+	}
+	return nil
+}
+
+// Load returns every stored job record. Records that cannot be parsed are
+// skipped with a warning.
+func (r *Registry) Load() ([]jobRecord, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry directory %s: %v", r.dir, err)
+	}
+
+	var records []jobRecord
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), recordExt) {
+			continue
+		}
+
+		path := filepath.Join(r.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Warn("failed to read job record", "path", path, "error", err)
+			continue
+		}
+		var record jobRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			logger.Warn("failed to parse job record", "path", path, "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Delete removes the record of a job.
+func (r *Registry) Delete(jobID string) error {
+	if err := os.Remove(r.recordPath(jobID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistrySaveLoadDelete(t *testing.T) {
+	dir := t.TempDir()
+	registry, err := NewRegistry(dir)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	want := jobRecord{
+		ID:      "job",
+		Owner:   "alice",
+		Command: "/bin/sleep",
+		Args:    []string{"10"},
+		PID:     42,
+		Status:  Status{State: StateRunning, StartedAt: time.Now().UTC().Truncate(time.Second)},
+	}
+	if err := registry.Save(want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// Unparsable records are skipped
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := registry.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Load() returned %d records, want 1", len(records))
+	}
+	got := records[0]
+	if got.Owner != want.Owner || got.PID != want.PID || len(got.Args) != 1 || got.Status.State != StateRunning || !got.Status.StartedAt.Equal(want.Status.StartedAt) {
+		t.Fatalf("Load() = %+v, want %+v", got, want)
+	}
+
+	if err := registry.Delete("job"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := registry.Delete("job"); err != nil {
+		t.Fatalf("Delete() of a missing record error = %v", err)
+	}
+}
+
+func TestRegistryConcurrentSaves(t *testing.T) {
+	dir := t.TempDir()
+	registry, err := NewRegistry(dir)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := registry.Save(jobRecord{ID: "job", PID: i}); err != nil {
+				t.Errorf("Save() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "job.json" {
+		t.Fatalf("registry holds %v, want only job.json", entries)
+	}
+}
@@ -0,0 +1,226 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultRootfsBinds are the host paths bind-mounted read-only into the root
+// filesystem of jobs, so that they find the host's shells, interpreters and
+// libraries. Only the files of /etc that programs commonly need are bound,
+// keeping secrets such as /etc/shadow out. Paths missing on the host are
+// skipped.
+var DefaultRootfsBinds = []string{
+	"/bin", "/sbin", "/lib", "/lib32", "/lib64", "/libx32", "/usr",
+	"/etc/passwd", "/etc/group", "/etc/hosts", "/etc/resolv.conf", "/etc/nsswitch.conf",
+	"/etc/ld.so.cache", "/etc/localtime", "/etc/alternatives", "/etc/ssl", "/etc/ca-certificates",
+}
+
+// DefaultRootfsStaging is the directory on which the init of a job assembles
+// its root filesystem. Each job mounts its root there in its own mount
+// namespace, so all jobs share the path.
+const DefaultRootfsStaging = "/run/sentry-run/rootfs"
+
+// DefaultRootfsLayers is the directory holding the writable overlay layers
+// of jobs started from an image.
+const DefaultRootfsLayers = "/var/lib/sentry-run/layers"
+
+// RootfsConfig describes how the root filesystems of isolated jobs are
+// assembled.
+type RootfsConfig struct {
+	// Base is a directory that becomes the read-only root of every isolated
+	// job. Without it, jobs started with a mount directory get an empty
+	// tmpfs root and other jobs keep the host's root.
+	Base string
+	// Binds are host paths bind-mounted read-only at the same place in the
+	// root. Nil means DefaultRootfsBinds.
+	Binds []string
+	// Images is the directory whose subdirectories are the images jobs can
+	// be started from. Empty disables images.
+	Images string
+	// Layers is the directory in which each job started from an image gets
+	// its upper and work directories. Empty means DefaultRootfsLayers.
+	Layers string
+	// MountRoots are the host directories under which jobs may mount a
+	// directory. Without them, jobs cannot mount host directories.
+	MountRoots []string
+}
+
+// Validate checks that the base directory exists and all paths are
+// absolute.
+func (c RootfsConfig) Validate() error {
+	if c.Base != "" {
+		if err := checkDir(c.Base); err != nil {
+			return fmt.Errorf("invalid rootfs base: %v", err)
+		}
+	}
+	if c.Images != "" {
+		if err := checkDir(c.Images); err != nil {
+			return fmt.Errorf("invalid rootfs images directory: %v", err)
+		}
+	}
+	for _, dir := range []string{c.Images, c.Layers} {
+		// overlayfs splits its options at commas and lower directories at colons
+		if strings.ContainsAny(dir, ",:") {
+			return fmt.Errorf("rootfs directory %q must not contain commas or colons", dir)
+		}
+	}
+	if c.Layers != "" && !filepath.IsAbs(c.Layers) {
+		return fmt.Errorf("rootfs layers directory %q is not an absolute path", c.Layers)
+	}
+	for _, path := range c.Binds {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("rootfs bind %q is not an absolute path", path)
+		}
+	}
+	for _, root := range c.MountRoots {
+		if err := checkDir(root); err != nil {
+			return fmt.Errorf("invalid mount root: %v", err)
+		}
+		if filepath.Clean(root) == "/" {
+			return fmt.Errorf("the host root cannot be a mount root")
+		}
+	}
+	return nil
+}
+
+// mountPath resolves the directory a job asked to mount. It must lie under
+// one of the mount roots and must neither contain nor be inside any of the
+// protected paths.
+func (c RootfsConfig) mountPath(path string, protected []string) (string, error) {
+	if err := checkDir(path); err != nil {
+		return "", err
+	}
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if path == "/" {
+		return "", fmt.Errorf("the host root cannot be mounted")
+	}
+	allowed := false
+	for _, root := range c.MountRoots {
+		if isWithin(path, resolvePath(root)) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		if len(c.MountRoots) == 0 {
+			return "", fmt.Errorf("the server allows no mount directories")
+		}
+		return "", fmt.Errorf("%s is not under a mount root (%s)", path, strings.Join(c.MountRoots, ", "))
+	}
+	for _, p := range protected {
+		p = resolvePath(p)
+		if isWithin(path, p) || isWithin(p, path) {
+			return "", fmt.Errorf("%s overlaps %s, which jobs cannot mount", path, p)
+		}
+	}
+	return path, nil
+}
+
+// resolvePath returns the absolute path of path with its symbolic links
+// resolved, or only made absolute if it does not exist.
+func resolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+// isWithin reports whether path is dir or below it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// checkDir checks that path is an absolute path to a directory.
+func checkDir(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%q is not an absolute path", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	return nil
+}
+
+// imagePath returns the directory of an image. Image names are single path
+// elements, so a job cannot name a directory outside the images directory.
+func (c RootfsConfig) imagePath(image string) (string, error) {
+	if c.Images == "" {
+		return "", fmt.Errorf("the server has no images directory, jobs cannot be started from images")
+	}
+	if image == "." || image == ".." || strings.ContainsAny(image, "/,:") {
+		return "", fmt.Errorf("invalid image name %q", image)
+	}
+	path := filepath.Join(c.Images, image)
+	if err := checkDir(path); err != nil {
+		return "", fmt.Errorf("unknown image %q: %v", image, err)
+	}
+	return path, nil
+}
+
+// createLayer creates the upper and work directories of a job started from
+// an image.
+func (c RootfsConfig) createLayer(jobID string) (string, error) {
+	dir := filepath.Join(c.Layers, jobID)
+	if err := os.MkdirAll(filepath.Join(dir, "work"), 0700); err != nil {
+		return "", fmt.Errorf("failed to create rootfs layer: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "upper"), 0755); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to create rootfs layer: %v", err)
+	}
+	return dir, nil
+}
+
+// UpperDir returns the directory holding the changes a job started from an
+// image made to it, or "" for other jobs.
+func (job *Job) UpperDir() string {
+	if job.LayerDir == "" {
+		return ""
+	}
+	return filepath.Join(job.LayerDir, "upper")
+}
+
+// removeLayer discards the changes a job made to its image, unless the job
+// asked to keep them.
+func (job *Job) removeLayer() {
+	if job.LayerDir == "" {
+		return
+	}
+	if job.KeepRootfs {
+		logger.Info("keeping job root filesystem changes", "job_id", job.ID, "upper_dir", job.UpperDir())
+		return
+	}
+	if err := os.RemoveAll(job.LayerDir); err != nil {
+		logger.Warn("failed to remove job root filesystem layer", "job_id", job.ID, "error", err)
+	}
+}
+
+// initRootfs tells the init of an isolated job how to assemble its root
+// filesystem.
+type initRootfs struct {
+	Staging string   `json:"staging"`
+	Base    string   `json:"base,omitempty"`
+	Binds   []string `json:"binds,omitempty"`
+	// Image, Upper and Work are the directories of an overlay root. Image
+	// is the read-only lower directory.
+	Image string `json:"image,omitempty"`
+	Upper string `json:"upper,omitempty"`
+	Work  string `json:"work,omitempty"`
+	// Mount is a host directory bind-mounted read-write at the same path,
+	// in which the job starts
+	Mount string `json:"mount,omitempty"`
+}
@@ -0,0 +1,299 @@
+package jobmanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// devices are the character devices created in a job's /dev.
+var devices = []struct {
+	name         string
+	major, minor uint32
+}{
+	{"null", 1, 3},
+	{"zero", 1, 5},
+	{"full", 1, 7},
+	{"random", 1, 8},
+	{"urandom", 1, 9},
+	{"tty", 5, 0},
+}
+
+// setupRootfs assembles the root filesystem of a job on the staging
+// directory and makes it the job's root with pivot_root. The root is an
+// overlay of the job's image and writable layer, an overlay of the base
+// directory and a tmpfs holding the job's mount points, remounted read-only
+// at the end, or an empty tmpfs. It
+// receives read-only bind mounts of the configured host paths, a /proc of
+// the job's PID namespace, a minimal /dev, a tmpfs /tmp and a read-write
+// bind mount of the job's mount directory, which becomes its working
+// directory. A bridged job's resolv.conf is bound over /etc/resolv.conf.
+func setupRootfs(config initRootfs, resolvConf string) error {
+	root := config.Staging
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %v", root, err)
+	}
+	switch {
+	case config.Image != "":
+		options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", config.Image, config.Upper, config.Work)
+		if err := syscall.Mount("overlay", root, "overlay", 0, options); err != nil {
+			return fmt.Errorf("failed to mount image overlay: %v", err)
+		}
+	case config.Base != "":
+		// The base is shared by all jobs, so mount points are created in a
+		// layer of the job's own. The overlay covers the tmpfs holding the
+		// layer but keeps using it.
+		if err := syscall.Mount("tmpfs", root, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=755"); err != nil {
+			return fmt.Errorf("failed to mount root tmpfs: %v", err)
+		}
+		upper, work := filepath.Join(root, "upper"), filepath.Join(root, "work")
+		for _, dir := range []string{upper, work} {
+			if err := os.Mkdir(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %v", dir, err)
+			}
+		}
+		options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", config.Base, upper, work)
+		if err := syscall.Mount("overlay", root, "overlay", 0, options); err != nil {
+			return fmt.Errorf("failed to mount rootfs base %s: %v", config.Base, err)
+		}
+	default:
+		if err := syscall.Mount("tmpfs", root, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=755"); err != nil {
+			return fmt.Errorf("failed to mount root tmpfs: %v", err)
+		}
+	}
+
+	for _, path := range config.Binds {
+		if err := bindHostPath(root, path, true); err != nil {
+			return err
+		}
+	}
+	if resolvConf != "" {
+		if err := bindResolvConf(root, resolvConf); err != nil {
+			return err
+		}
+	}
+
+	proc := filepath.Join(root, "proc")
+	if err := os.MkdirAll(proc, 0555); err != nil {
+		return fmt.Errorf("failed to create %s: %v", proc, err)
+	}
+	if err := syscall.Mount("proc", proc, "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("failed to mount /proc: %v", err)
+	}
+	if err := setupDev(filepath.Join(root, "dev")); err != nil {
+		return err
+	}
+	tmp := filepath.Join(root, "tmp")
+	if err := os.MkdirAll(tmp, 01777); err != nil {
+		return fmt.Errorf("failed to create %s: %v", tmp, err)
+	}
+	if err := syscall.Mount("tmpfs", tmp, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777"); err != nil {
+		return fmt.Errorf("failed to mount /tmp: %v", err)
+	}
+	if config.Mount != "" {
+		if err := bindHostPath(root, config.Mount, false); err != nil {
+			return err
+		}
+	}
+
+	if err := pivotRoot(root); err != nil {
+		return err
+	}
+	if config.Base != "" && config.Image == "" {
+		if err := syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+			return fmt.Errorf("failed to make the root read-only: %v", err)
+		}
+	}
+	if config.Mount != "" {
+		return syscall.Chdir(config.Mount)
+	}
+	return nil
+}
+
+// protectHostRoot makes every mount of a job that keeps the host's root
+// read-only, gives it a private /tmp and /dev/shm, and covers the hidden
+// directories with an empty read-only tmpfs. It only changes the job's
+// mount namespace.
+func protectHostRoot(hidden []string) error {
+	attr := unix.MountAttr{Attr_set: unix.MOUNT_ATTR_RDONLY}
+	if err := unix.MountSetattr(unix.AT_FDCWD, "/", unix.AT_RECURSIVE, &attr); err != nil {
+		return fmt.Errorf("failed to make the host root read-only: %v", err)
+	}
+	for _, dir := range []string{"/tmp", "/dev/shm"} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := syscall.Mount("tmpfs", dir, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777"); err != nil {
+			return fmt.Errorf("failed to mount %s: %v", dir, err)
+		}
+	}
+	for _, dir := range hidden {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := syscall.Mount("tmpfs", dir, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC|syscall.MS_RDONLY, "size=0"); err != nil {
+			return fmt.Errorf("failed to hide %s: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+// bindResolvConf bind-mounts resolvConf read-only over /etc/resolv.conf
+// under root. A symbolic link there, which would resolve on the host, is
+// replaced with a file to mount on.
+func bindResolvConf(root, resolvConf string) error {
+	target := filepath.Join(root, "etc", "resolv.conf")
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(target); err != nil {
+			return fmt.Errorf("failed to replace %s: %v", target, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(target), err)
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create mount point %s: %v", target, err)
+	}
+	file.Close()
+	return bindReadOnly(resolvConf, target)
+}
+
+// bindReadOnly bind-mounts the file source read-only on target.
+func bindReadOnly(source, target string) error {
+	if err := syscall.Mount(source, target, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to bind mount %s: %v", source, err)
+	}
+	if err := syscall.Mount("", target, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+		return fmt.Errorf("failed to make %s read-only: %v", target, err)
+	}
+	return nil
+}
+
+// bindHostPath bind-mounts the host path at the same place under root.
+// Missing paths are skipped and symbolic links, such as /lib on merged /usr
+// systems, are recreated instead.
+func bindHostPath(root, path string, readOnly bool) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(target), err)
+	}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(target); err == nil {
+			return nil
+		}
+		return os.Symlink(link, target)
+	case info.IsDir():
+		err = os.MkdirAll(target, 0755)
+	default:
+		var file *os.File
+		if file, err = os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0644); err == nil {
+			file.Close()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create mount point %s: %v", target, err)
+	}
+
+	if err := syscall.Mount(path, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to bind mount %s: %v", path, err)
+	}
+	if readOnly {
+		if err := syscall.Mount("", target, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+			return fmt.Errorf("failed to make %s read-only: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// setupDev mounts a tmpfs /dev with the basic character devices, the
+// standard stream links and a /dev/shm.
+func setupDev(dev string) error {
+	if err := os.MkdirAll(dev, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", dev, err)
+	}
+	if err := syscall.Mount("tmpfs", dev, "tmpfs", syscall.MS_NOSUID|syscall.MS_STRICTATIME, "mode=755,size=64k"); err != nil {
+		return fmt.Errorf("failed to mount /dev: %v", err)
+	}
+	for _, device := range devices {
+		path := filepath.Join(dev, device.name)
+		if err := syscall.Mknod(path, syscall.S_IFCHR|0666, int(unix.Mkdev(device.major, device.minor))); err != nil {
+			return fmt.Errorf("failed to create /dev/%s: %v", device.name, err)
+		}
+		// Mknod applies the umask
+		if err := os.Chmod(path, 0666); err != nil {
+			return err
+		}
+	}
+	for name, target := range map[string]string{
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+	} {
+		if err := os.Symlink(target, filepath.Join(dev, name)); err != nil {
+			return fmt.Errorf("failed to create /dev/%s: %v", name, err)
+		}
+	}
+
+	shm := filepath.Join(dev, "shm")
+	if err := os.Mkdir(shm, 01777); err != nil {
+		return fmt.Errorf("failed to create /dev/shm: %v", err)
+	}
+	if err := syscall.Mount("shm", shm, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, "mode=1777"); err != nil {
+		return fmt.Errorf("failed to mount /dev/shm: %v", err)
+	}
+	return nil
+}
+
+// pivotRoot makes root the root of the mount namespace and detaches the old
+// root, so no path leads back to the host's filesystem. pivot_root(".", ".")
+// stacks the old root on top of the new one, which avoids creating a
+// directory for it in root.
+func pivotRoot(root string) error {
+	oldRoot, err := syscall.Open("/", syscall.O_DIRECTORY|syscall.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open /: %v", err)
+	}
+	defer syscall.Close(oldRoot)
+	newRoot, err := syscall.Open(root, syscall.O_DIRECTORY|syscall.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", root, err)
+	}
+	defer syscall.Close(newRoot)
+
+	if err := syscall.Fchdir(newRoot); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("failed to pivot_root to %s: %v", root, err)
+	}
+	if err := syscall.Fchdir(oldRoot); err != nil {
+		return err
+	}
+	// Keep the detach from propagating to the mounts of the new root
+	if err := syscall.Mount("", ".", "", syscall.MS_SLAVE|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to prepare the old root for detaching: %v", err)
+	}
+	if err := syscall.Unmount(".", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach the old root: %v", err)
+	}
+	return syscall.Chdir("/")
+}
@@ -0,0 +1,185 @@
+package jobmanager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+// runInRootfs runs script with /bin/sh in an isolated job and returns its
+// stdout.
+func runInRootfs(t *testing.T, manager *JobManager, mount, script string) string {
+	t.Helper()
+	job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", script}, limits.Limits{}, StartOptions{Mount: mount})
+	if err != nil {
+		t.Fatalf("StartJob() error = %v", err)
+	}
+	<-job.Done()
+
+	stdout, stderr, err := manager.GetJobOutput(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOutput() error = %v", err)
+	}
+	if status := job.Status(); status.State != StateExited || status.ExitCode != 0 {
+		t.Fatalf("status = %+v, want exited 0 (stderr %q)", status, stderr)
+	}
+	return string(stdout)
+}
+
+func TestMountedJobRootfs(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	mount := t.TempDir()
+	if err := os.WriteFile(filepath.Join(mount, "input"), []byte("data\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hostOnly := t.TempDir()
+
+	manager := New(WithIsolation(true), WithRootfs(RootfsConfig{MountRoots: []string{os.TempDir()}}))
+	script := `pwd; cat input; echo out > output
+echo x > /tmp/scratch && echo tmp-writable
+echo x > /dev/null && echo dev-null
+touch /usr/sentry-test 2>/dev/null || echo usr-read-only
+test -e ` + hostOnly + ` || echo host-hidden
+test -e /root || echo root-hidden
+test -e /etc/shadow || echo shadow-hidden
+mount -o remount,rw /usr 2>/dev/null || echo remount-denied
+mknod /tmp/disk b 8 0 2>/dev/null || echo mknod-denied`
+	stdout := runInRootfs(t, manager, mount, script)
+
+	want := mount + "\ndata\ntmp-writable\ndev-null\nusr-read-only\nhost-hidden\nroot-hidden\nshadow-hidden\nremount-denied\nmknod-denied\n"
+	if stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+	if data, err := os.ReadFile(filepath.Join(mount, "output")); err != nil || string(data) != "out\n" {
+		t.Errorf("output = (%q, %v), want the job's write in the mount directory", data, err)
+	}
+	if _, err := os.Stat("/usr/sentry-test"); err == nil {
+		os.Remove("/usr/sentry-test")
+		t.Error("job wrote to the host's /usr")
+	}
+}
+
+func TestBaseRootfsIsReadOnly(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "marker"), []byte("base\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mount := t.TempDir()
+
+	manager := New(WithIsolation(true), WithRootfs(RootfsConfig{Base: base, MountRoots: []string{os.TempDir()}}))
+	stdout := runInRootfs(t, manager, mount, "cat /marker; touch /new 2>/dev/null || echo root-read-only; test -d /usr && echo usr-bound")
+	if want := "base\nroot-read-only\nusr-bound\n"; stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+
+	// The mount points of the job stay out of the shared base
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "marker" {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("base holds %v after the job, want only marker", names)
+	}
+}
+
+// newShellImage creates an image in images holding /bin/sh and the shared
+// libraries it needs, as listed by ldd.
+func newShellImage(t *testing.T, images, name string) string {
+	t.Helper()
+	out, err := exec.Command("ldd", "/bin/sh").Output()
+	if err != nil {
+		t.Skipf("cannot list the libraries of /bin/sh: %v", err)
+	}
+	image := filepath.Join(images, name)
+	files := []string{"/bin/sh"}
+	for _, field := range strings.Fields(string(out)) {
+		if strings.HasPrefix(field, "/") {
+			files = append(files, field)
+		}
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		target := filepath.Join(image, file)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, data, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(image, "marker"), []byte("pristine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return image
+}
+
+func TestImageJobCopyOnWrite(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces requires root")
+	}
+	images, layers := t.TempDir(), t.TempDir()
+	image := newShellImage(t, images, "shell")
+	manager := New(WithIsolation(true), WithRootfs(RootfsConfig{Images: images, Layers: layers, MountRoots: []string{os.TempDir()}}))
+
+	run := func(opts StartOptions) (*Job, string) {
+		job, err := manager.StartJob("alice", "/bin/sh", []string{"-c", "read line < /marker; echo $line; echo changed > /marker; echo x > /created"}, limits.Limits{}, opts)
+		if err != nil {
+			t.Fatalf("StartJob() error = %v", err)
+		}
+		<-job.Done()
+		stdout, stderr, err := manager.GetJobOutput(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobOutput() error = %v", err)
+		}
+		if status := job.Status(); status.State != StateExited || status.ExitCode != 0 {
+			t.Fatalf("status = %+v, want exited 0 (stderr %q)", status, stderr)
+		}
+		return job, string(stdout)
+	}
+
+	discarded, stdout := run(StartOptions{Image: "shell"})
+	if stdout != "pristine\n" {
+		t.Fatalf("stdout = %q, want the image's marker", stdout)
+	}
+	if data, err := os.ReadFile(filepath.Join(discarded.UpperDir(), "marker")); err != nil || string(data) != "changed\n" {
+		t.Errorf("upper marker = (%q, %v), want the job's change", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(image, "marker")); string(data) != "pristine\n" {
+		t.Fatalf("image marker = %q, job wrote to the image", data)
+	}
+
+	// Every job starts from the pristine image
+	kept, stdout := run(StartOptions{Image: "shell", KeepRootfs: true})
+	if stdout != "pristine\n" {
+		t.Fatalf("second job stdout = %q, want the image's marker", stdout)
+	}
+
+	for _, job := range []*Job{discarded, kept} {
+		if err := manager.RemoveJob(job.ID); err != nil {
+			t.Fatalf("RemoveJob() error = %v", err)
+		}
+	}
+	if _, err := os.Stat(discarded.LayerDir); !os.IsNotExist(err) {
+		t.Errorf("layer of removed job still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(kept.UpperDir(), "created")); err != nil {
+		t.Errorf("layer of job with KeepRootfs was discarded: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package jobmanager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+func TestRootfsConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (RootfsConfig{Base: dir, Binds: []string{"/usr", "/missing"}, MountRoots: []string{dir}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for _, config := range []RootfsConfig{
+		{Base: "relative"},
+		{Base: filepath.Join(dir, "missing")},
+		{Base: file},
+		{Binds: []string{"usr"}},
+		{Images: "relative"},
+		{Images: dir, Layers: "relative"},
+		{Layers: "/var/lib/a,b"},
+		{MountRoots: []string{"relative"}},
+		{MountRoots: []string{file}},
+		{MountRoots: []string{"/"}},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", config)
+		}
+	}
+}
+
+func TestStartJobValidatesMount(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	protected := filepath.Join(root, "data")
+	for _, dir := range []string{filepath.Join(protected, "logs"), filepath.Join(root, "project")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := New(WithIsolation(true), WithRootfs(RootfsConfig{MountRoots: []string{root}}), WithProtectedPaths(protected))
+	for _, mount := range []string{
+		"relative",
+		filepath.Join(root, "missing"),
+		"/",
+		"/etc",
+		outside,
+		filepath.Join(root, "escape"),
+		protected,
+		filepath.Join(protected, "logs"),
+		root,
+	} {
+		_, err := manager.StartJob("alice", "true", nil, limits.Limits{}, StartOptions{Mount: mount})
+		var invalid *InvalidOptionsError
+		if !errors.As(err, &invalid) || !strings.Contains(err.Error(), "invalid mount directory") {
+			t.Errorf("StartJob(mount %q) error = %v, want invalid mount directory", mount, err)
+		}
+	}
+
+	if _, err := New(WithIsolation(true)).StartJob("alice", "true", nil, limits.Limits{}, StartOptions{Mount: root}); err == nil || !strings.Contains(err.Error(), "allows no mount directories") {
+		t.Errorf("StartJob() without mount roots error = %v, want no mount directories", err)
+	}
+
+	manager = New(WithIsolation(false))
+	if _, err := manager.StartJob("alice", "true", nil, limits.Limits{}, StartOptions{Mount: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "requires job isolation") {
+		t.Errorf("StartJob() without isolation error = %v, want isolation error", err)
+	}
+	if jobs := manager.ListJobs(); len(jobs) != 0 {
+		t.Errorf("ListJobs() = %v, want no jobs", jobs)
+	}
+}
+
+func TestRootfsMountPath(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("project", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	config := RootfsConfig{MountRoots: []string{root}}
+	want, err := filepath.EvalSymlinks(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mount := range []string{project, filepath.Join(root, "link"), project + "/"} {
+		if path, err := config.mountPath(mount, []string{"/usr", filepath.Join(root, "data")}); err != nil || path != want {
+			t.Errorf("mountPath(%q) = (%q, %v), want %q", mount, path, err, want)
+		}
+	}
+}
+
+func TestRootfsImagePath(t *testing.T) {
+	images := t.TempDir()
+	if err := os.Mkdir(filepath.Join(images, "toolchain"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := RootfsConfig{Images: images}
+
+	if path, err := config.imagePath("toolchain"); err != nil || path != filepath.Join(images, "toolchain") {
+		t.Errorf("imagePath(toolchain) = (%q, %v), want the image directory", path, err)
+	}
+	for _, image := range []string{".", "..", "../toolchain", "a,upperdir=/", "missing"} {
+		if _, err := config.imagePath(image); err == nil {
+			t.Errorf("imagePath(%q) succeeded, want error", image)
+		}
+	}
+	if _, err := (RootfsConfig{}).imagePath("toolchain"); err == nil {
+		t.Error("imagePath() without an images directory succeeded, want error")
+	}
+}
+
+func TestStartJobImageRequiresIsolation(t *testing.T) {
+	images := t.TempDir()
+	if err := os.Mkdir(filepath.Join(images, "toolchain"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	layers := t.TempDir()
+	manager := New(WithIsolation(false), WithRootfs(RootfsConfig{Images: images, Layers: layers}))
+	if _, err := manager.StartJob("alice", "true", nil, limits.Limits{}, StartOptions{Image: "toolchain"}); err == nil || !strings.Contains(err.Error(), "requires job isolation") {
+		t.Errorf("StartJob() error = %v, want isolation error", err)
+	}
+	if entries, _ := os.ReadDir(layers); len(entries) != 0 {
+		t.Errorf("layers = %v, want none", entries)
+	}
+}
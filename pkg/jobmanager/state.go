@@ -0,0 +1,180 @@
+package jobmanager
+
+import (
+	"syscall"
+	"time"
+)
+
+// State is a step in the job lifecycle.
+type State string
+
+const (
+	// StatePending is a job that has been registered but not started yet.
+	StatePending State = "pending"
+	// StateRunning is a job whose process is alive.
+	StateRunning State = "running"
+	// StateExited is a job whose process exited on its own.
+	StateExited State = "exited"
+	// StateKilled is a job whose process was terminated by a signal.
+	StateKilled State = "killed"
+	// StateFailedToStart is a job whose process could not be started or placed in its cgroup.
+	StateFailedToStart State = "failed-to-start"
+	// StateOOMKilled is a job whose process was killed by the kernel OOM killer.
+	StateOOMKilled State = "oom-killed"
+	// StateLost is a job that was running when the server stopped and whose
+	// processes were gone when it restarted.
+	StateLost State = "lost"
+)
+
+// Finished reports whether the state is terminal.
+func (s State) Finished() bool {
+	switch s {
+	case StateExited, StateKilled, StateFailedToStart, StateOOMKilled, StateLost:
+		return true
+	}
+	return false
+}
+
+// Status is a point-in-time snapshot of a job's lifecycle.
+type Status struct {
+	State State `json:"state"`
+	// ExitCode is the process exit code, or -1 if it did not exit normally.
+	ExitCode int `json:"exit_code"`
+	// Signal is the signal that terminated the process, if any.
+	Signal     syscall.Signal `json:"signal,omitempty"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	// Error describes why the job failed to start, was OOM-killed or why
+	// its result is unknown.
+	Error string `json:"error,omitempty"`
+	// OOMEvents counts the times the job's cgroup reached its memory limit
+	// and the OOM killer was invoked (memory.events oom).
+	OOMEvents uint64 `json:"oom_events,omitempty"`
+	// OOMKills counts the job's processes killed by the OOM killer
+	// (memory.events oom_kill).
+	OOMKills uint64 `json:"oom_kills,omitempty"`
+	// Stalls counts the times the pressure trigger fired on each resource.
+	Stalls Stalls `json:"stalls"`
+}
+
+// IsRunning reports whether the job process is alive.
+func (s Status) IsRunning() bool {
+	return s.State == StateRunning
+}
+
+// Status returns a snapshot of the job's lifecycle state.
+func (job *Job) Status() Status {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.status
+}
+
+// Done returns a channel that is closed once the job has finished.
+func (job *Job) Done() <-chan struct{} {
+	return job.done
+}
+
+// markRunning records that the job process has started.
+func (job *Job) markRunning() {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.status.State = StateRunning
+	job.status.StartedAt = time.Now()
+}
+
+// markFailed records that the job could not be started.
+func (job *Job) markFailed(err error) {
+	job.mu.Lock()
+	job.status.State = StateFailedToStart
+	job.status.ExitCode = -1
+	job.status.Error = err.Error()
+	job.status.FinishedAt = time.Now()
+	job.mu.Unlock()
+	close(job.done)
+}
+
+// markFinished records the result of waiting on the job process, or for an
+// isolated job the result its init reported for the command. A job that did
+// not exit successfully after the OOM killer fired in its cgroup, whether
+// on the job process or one of its children, is OOM-killed.
+func (job *Job) markFinished(waitErr error) {
+	state := StateExited
+	exitCode := -1
+	var signal syscall.Signal
+
+	if ps := job.Cmd.ProcessState; ps != nil {
+		exitCode = ps.ExitCode()
+		ws, ok := ps.Sys().(syscall.WaitStatus)
+		if job.initStatus != nil {
+			// The init of an isolated job reports how its command ended
+			if status, reported := readInitStatus(job.initStatus); reported {
+				ws, ok = status, true
+				exitCode = status.ExitStatus()
+			}
+		}
+		if ok && ws.Signaled() {
+			signal = ws.Signal()
+			state = StateKilled
+		}
+	} else if waitErr != nil {
+		state = StateKilled
+	}
+
+	// The watcher may not have seen the last update before the cgroup goes
+	job.recordMemoryEvents(getCgroupPath(job.ID))
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.status.OOMKills > 0 && (state == StateKilled || exitCode != 0) {
+		state = StateOOMKilled
+		job.status.Error = oomError(job.status.OOMKills, job.Limits.MemoryMax)
+	}
+	job.status.State = state
+	job.status.ExitCode = exitCode
+	job.status.Signal = signal
+	job.status.FinishedAt = time.Now()
+	job.waitErr = waitErr
+}
+
+// markLost records that the job's processes disappeared while the server
+// was not running.
+func (job *Job) markLost() {
+	job.mu.Lock()
+	job.status.State = StateLost
+	job.status.ExitCode = -1
+	job.status.Error = "job processes were gone when the server restarted"
+	job.status.FinishedAt = time.Now()
+	job.mu.Unlock()
+	close(job.done)
+}
+
+// markAdoptedFinished records the end of a job re-adopted after a restart.
+// Its exit status cannot be collected, so the state is inferred from the
+// signals the manager delivered and the OOM counter of its cgroup.
+func (job *Job) markAdoptedFinished(cgroupPath string) {
+	job.recordMemoryEvents(cgroupPath)
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.status.ExitCode = -1
+	job.status.FinishedAt = time.Now()
+	switch {
+	case job.status.OOMKills > 0:
+		job.status.State = StateOOMKilled
+		job.status.Signal = syscall.SIGKILL
+		job.status.Error = oomError(job.status.OOMKills, job.Limits.MemoryMax)
+	case job.lastSignal != 0:
+		job.status.State = StateKilled
+		job.status.Signal = job.lastSignal
+	default:
+		job.status.State = StateExited
+		job.status.Error = "exit status of a job re-adopted after restart is unknown"
+	}
+}
+
+// exitError returns the error the job's process exited with, if any.
+func (job *Job) exitError() error {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.waitErr
+}
@@ -0,0 +1,197 @@
+package jobmanager
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stats is the resource usage of a job, read from its cgroup.
+type Stats struct {
+	CollectedAt time.Time
+	CPU         CPUStats
+	Memory      MemoryStats
+	IO          []IOStats
+	PidsCurrent uint64
+	Pressure    JobPressure
+}
+
+// CPUStats holds the counters of cpu.stat, in microseconds.
+type CPUStats struct {
+	UsageUsec     uint64
+	UserUsec      uint64
+	SystemUsec    uint64
+	NrPeriods     uint64
+	NrThrottled   uint64
+	ThrottledUsec uint64
+}
+
+// MemoryStats holds memory.current, memory.peak, memory.stat and
+// memory.events. Peak is zero on kernels without memory.peak.
+type MemoryStats struct {
+	Current uint64
+	Peak    uint64
+	Anon    uint64
+	File    uint64
+	// Stat is the full contents of memory.stat
+	Stat   map[string]uint64
+	Events MemoryEvents
+}
+
+// MemoryEvents holds the counters of memory.events.
+type MemoryEvents struct {
+	Low     uint64
+	High    uint64
+	Max     uint64
+	OOM     uint64
+	OOMKill uint64
+}
+
+// IOStats holds the io.stat counters of one device.
+type IOStats struct {
+	Device       string
+	ReadBytes    uint64
+	WriteBytes   uint64
+	ReadIOs      uint64
+	WriteIOs     uint64
+	DiscardBytes uint64
+	DiscardIOs   uint64
+}
+
+// GetJobStats reads the resource usage of a running job from its cgroup.
+// Interface files of controllers that are not enabled are skipped.
+func (m *JobManager) GetJobStats(jobID string) (Stats, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return Stats{}, err
+	}
+	if job.Status().State.Finished() {
+		return Stats{}, fmt.Errorf("job %s is not running", jobID)
+	}
+	cgroupPath := getCgroupPath(jobID)
+	if _, err := os.Stat(cgroupPath); err != nil {
+		return Stats{}, fmt.Errorf("job %s was started without a cgroup, it has no usage statistics", jobID)
+	}
+
+	stats := Stats{CollectedAt: time.Now()}
+	var errs []error
+	optional := func(err error) {
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	cpu, err := readFlatKeyed(jobID, "cpu.stat")
+	optional(err)
+	stats.CPU = CPUStats{
+		UsageUsec:     cpu["usage_usec"],
+		UserUsec:      cpu["user_usec"],
+		SystemUsec:    cpu["system_usec"],
+		NrPeriods:     cpu["nr_periods"],
+		NrThrottled:   cpu["nr_throttled"],
+		ThrottledUsec: cpu["throttled_usec"],
+	}
+
+	stats.Memory.Current, err = readCgroupUint(jobID, "memory.current")
+	optional(err)
+	stats.Memory.Peak, err = readCgroupUint(jobID, "memory.peak")
+	optional(err)
+	stats.Memory.Stat, err = readFlatKeyed(jobID, "memory.stat")
+	optional(err)
+	stats.Memory.Anon = stats.Memory.Stat["anon"]
+	stats.Memory.File = stats.Memory.Stat["file"]
+	events, err := readFlatKeyed(jobID, "memory.events")
+	optional(err)
+	stats.Memory.Events = MemoryEvents{
+		Low:     events["low"],
+		High:    events["high"],
+		Max:     events["max"],
+		OOM:     events["oom"],
+		OOMKill: events["oom_kill"],
+	}
+
+	stats.IO, err = readIOStat(jobID)
+	optional(err)
+	stats.PidsCurrent, err = readCgroupUint(jobID, "pids.current")
+	optional(err)
+	stats.Pressure, err = readJobPressure(jobID)
+	optional(err)
+
+	if len(errs) > 0 {
+		return Stats{}, fmt.Errorf("failed to read statistics of job %s: %v", jobID, errors.Join(errs...))
+	}
+	return stats, nil
+}
+
+// readFlatKeyed reads a cgroup file of "key value" lines, such as cpu.stat.
+func readFlatKeyed(jobID, name string) (map[string]uint64, error) {
+	return readFlatKeyedFile(filepath.Join(getCgroupPath(jobID), name))
+}
+
+// readFlatKeyedFile reads a file of "key value" lines.
+func readFlatKeyedFile(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", filepath.Base(path), err)
+		}
+		values[key] = n
+	}
+	return values, nil
+}
+
+// readIOStat reads io.stat, which has one "major:minor key=value ..." line
+// per device.
+func readIOStat(jobID string) ([]IOStats, error) {
+	data, err := os.ReadFile(filepath.Join(getCgroupPath(jobID), "io.stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []IOStats
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		device := IOStats{Device: fields[0]}
+		counters := map[string]*uint64{
+			"rbytes": &device.ReadBytes,
+			"wbytes": &device.WriteBytes,
+			"rios":   &device.ReadIOs,
+			"wios":   &device.WriteIOs,
+			"dbytes": &device.DiscardBytes,
+			"dios":   &device.DiscardIOs,
+		}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			counter, ok := counters[key]
+			if !ok {
+				continue
+			}
+			if *counter, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("failed to parse io.stat: %v", err)
+			}
+		}
+		stats = append(stats, device)
+	}
+	return stats, nil
+}
@@ -0,0 +1,62 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetJobStats(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+	files := map[string]string{
+		"cpu.stat":       "usage_usec 1500\nuser_usec 1000\nsystem_usec 500\nnr_periods 10\nnr_throttled 2\nthrottled_usec 300\n",
+		"memory.current": "4096\n",
+		"memory.stat":    "anon 1024\nfile 2048\nshmem 0\n",
+		"memory.events":  "low 0\nhigh 3\nmax 1\noom 0\noom_kill 0\n",
+		"io.stat":        "259:0 rbytes=100 wbytes=200 rios=1 wios=2 dbytes=0 dios=0\n",
+		"pids.current":   "3\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, "sentry-run-job-1", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := New()
+	manager.jobs.Store("job-1", &Job{ID: "job-1", status: Status{State: StateRunning}, done: make(chan struct{})})
+
+	stats, err := manager.GetJobStats("job-1")
+	if err != nil {
+		t.Fatalf("GetJobStats() error = %v", err)
+	}
+	if stats.CPU != (CPUStats{UsageUsec: 1500, UserUsec: 1000, SystemUsec: 500, NrPeriods: 10, NrThrottled: 2, ThrottledUsec: 300}) {
+		t.Errorf("CPU = %+v", stats.CPU)
+	}
+	// memory.peak is missing, as on kernels before 5.19
+	if stats.Memory.Current != 4096 || stats.Memory.Peak != 0 || stats.Memory.Anon != 1024 || stats.Memory.File != 2048 || stats.Memory.Stat["shmem"] != 0 {
+		t.Errorf("Memory = %+v", stats.Memory)
+	}
+	if stats.Memory.Events != (MemoryEvents{High: 3, Max: 1}) {
+		t.Errorf("Events = %+v", stats.Memory.Events)
+	}
+	if want := []IOStats{{Device: "259:0", ReadBytes: 100, WriteBytes: 200, ReadIOs: 1, WriteIOs: 2}}; !reflect.DeepEqual(stats.IO, want) {
+		t.Errorf("IO = %+v, want %+v", stats.IO, want)
+	}
+	if stats.PidsCurrent != 3 || stats.CollectedAt.IsZero() {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestGetJobStatsRequiresRunningJobWithCgroup(t *testing.T) {
+	fakeCgroupRoot(t, "sentry-run-exited")
+	manager := New()
+	manager.jobs.Store("exited", &Job{ID: "exited", status: Status{State: StateExited}, done: make(chan struct{})})
+	manager.jobs.Store("unlimited", &Job{ID: "unlimited", status: Status{State: StateRunning}, done: make(chan struct{})})
+
+	for _, jobID := range []string{"missing", "exited", "unlimited"} {
+		if _, err := manager.GetJobStats(jobID); err == nil {
+			t.Errorf("GetJobStats(%q) succeeded, want error", jobID)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package jobmanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+// LimitChange records an update of a running job's resource limits.
+type LimitChange struct {
+	ChangedAt time.Time     `json:"changed_at"`
+	Previous  limits.Limits `json:"previous"`
+	Limits    limits.Limits `json:"limits"`
+}
+
+// ResourceLimits returns the job's current resource limits.
+func (job *Job) ResourceLimits() limits.Limits {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.Limits
+}
+
+// Devices returns the "major:minor" of the disks the job's IO limits apply
+// to, separated by commas.
+func (job *Job) Devices() string {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.DeviceId
+}
+
+// LimitChanges returns the updates of the job's limits, oldest first.
+func (job *Job) LimitChanges() []LimitChange {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return append([]LimitChange(nil), job.limitChanges...)
+}
+
+// UpdateJobLimits applies the limits set in update to the cgroup of a
+// running job, keeping its other limits, and returns the job's new limits.
+// Only the interface files of the limits in update are rewritten; if one
+// of them is rejected the earlier ones stay applied and the job's recorded
+// limits are left unchanged.
+func (m *JobManager) UpdateJobLimits(jobID string, update limits.Limits) (limits.Limits, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return limits.Limits{}, err
+	}
+	if update.IsZero() {
+		return limits.Limits{}, errors.New("no limits to update")
+	}
+
+	job.updateMu.Lock()
+	defer job.updateMu.Unlock()
+
+	job.mu.Lock()
+	running := job.status.IsRunning()
+	previous := job.Limits
+	job.mu.Unlock()
+	if !running {
+		return limits.Limits{}, fmt.Errorf("job %s is not running", jobID)
+	}
+
+	cgroupPath := getCgroupPath(jobID)
+	if _, err := os.Stat(cgroupPath); err != nil {
+		return limits.Limits{}, fmt.Errorf("job %s was started without a cgroup, its limits cannot be updated", jobID)
+	}
+
+	merged := previous.Merge(update)
+	if err := merged.Validate(); err != nil {
+		return limits.Limits{}, fmt.Errorf("invalid limits: %v", err)
+	}
+	mergedIO, err := resolveIO(merged.IO)
+	if err != nil {
+		return limits.Limits{}, err
+	}
+	if update.IO, err = resolveIO(update.IO); err != nil {
+		return limits.Limits{}, err
+	}
+	if err := setLimits(cgroupPath, update); err != nil {
+		return limits.Limits{}, err
+	}
+
+	job.mu.Lock()
+	job.Limits = merged
+	job.DeviceId = deviceIDs(mergedIO)
+	job.limitChanges = append(job.limitChanges, LimitChange{ChangedAt: time.Now(), Previous: previous, Limits: merged})
+	job.mu.Unlock()
+	m.saveRecord(job)
+
+	logger.Info("updated job limits", "job_id", jobID)
+	return merged, nil
+}
@@ -0,0 +1,68 @@
+package jobmanager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+func TestUpdateJobLimits(t *testing.T) {
+	root := fakeCgroupRoot(t, "sentry-run-job-1")
+	manager := New()
+	job := &Job{
+		ID:     "job-1",
+		Limits: limits.Limits{MemoryMax: 1 << 30, PidsMax: 64},
+		status: Status{State: StateRunning},
+		done:   make(chan struct{}),
+	}
+	manager.jobs.Store(job.ID, job)
+
+	got, err := manager.UpdateJobLimits("job-1", limits.Limits{MemoryMax: 2 << 30})
+	if err != nil {
+		t.Fatalf("UpdateJobLimits() error = %v", err)
+	}
+	if got.MemoryMax != 2<<30 || got.PidsMax != 64 || !reflect.DeepEqual(job.ResourceLimits(), got) {
+		t.Fatalf("limits = %+v, want memory raised and the PID limit kept", got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "sentry-run-job-1", "memory.max"))
+	if err != nil || string(data) != "2147483648" {
+		t.Fatalf("memory.max = %q (%v), want 2147483648", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "sentry-run-job-1", "pids.max")); !os.IsNotExist(err) {
+		t.Fatalf("pids.max was rewritten: %v", err)
+	}
+
+	changes := job.LimitChanges()
+	if len(changes) != 1 || changes[0].Previous.MemoryMax != 1<<30 || !reflect.DeepEqual(changes[0].Limits, got) || changes[0].ChangedAt.IsZero() {
+		t.Fatalf("changes = %+v, want one change from 1G to 2G", changes)
+	}
+}
+
+func TestUpdateJobLimitsRejectsInvalidUpdates(t *testing.T) {
+	fakeCgroupRoot(t, "sentry-run-running")
+	manager := New()
+	manager.jobs.Store("running", &Job{ID: "running", status: Status{State: StateRunning}, done: make(chan struct{})})
+	manager.jobs.Store("exited", &Job{ID: "exited", status: Status{State: StateExited}, done: make(chan struct{})})
+	manager.jobs.Store("unlimited", &Job{ID: "unlimited", status: Status{State: StateRunning}, done: make(chan struct{})})
+
+	tests := []struct {
+		name   string
+		jobID  string
+		update limits.Limits
+	}{
+		{"unknown job", "missing", limits.Limits{MemoryMax: 1 << 30}},
+		{"nothing to update", "running", limits.Limits{}},
+		{"finished job", "exited", limits.Limits{MemoryMax: 1 << 30}},
+		{"no cgroup", "unlimited", limits.Limits{MemoryMax: 1 << 30}},
+		{"invalid", "running", limits.Limits{CPUWeight: 20000}},
+	}
+	for _, tt := range tests {
+		if _, err := manager.UpdateJobLimits(tt.jobID, tt.update); err == nil {
+			t.Errorf("%s: UpdateJobLimits() succeeded, want error", tt.name)
+		}
+	}
+}
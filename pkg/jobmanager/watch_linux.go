@@ -0,0 +1,51 @@
+package jobmanager
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// watchFile calls onChange whenever the file at path is modified, until
+// stop is closed or the file is removed. It only returns an error if the
+// file cannot be watched.
+func watchFile(path string, stop <-chan struct{}, onChange func()) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC)
+	if err != nil {
+		return fmt.Errorf("failed to initialize inotify: %v", err)
+	}
+	if _, err := syscall.InotifyAddWatch(fd, path, syscall.IN_MODIFY); err != nil {
+		syscall.Close(fd)
+		return fmt.Errorf("failed to watch %s: %v", path, err)
+	}
+
+	// A non-blocking descriptor is served by the runtime poller, so closing
+	// the file interrupts a pending Read
+	events := os.NewFile(uintptr(fd), "inotify")
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		select {
+		case <-stop:
+		case <-closed:
+		}
+		events.Close()
+	}()
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := events.Read(buf)
+		if err != nil {
+			return nil
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				return nil
+			}
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+		}
+		onChange()
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package jobmanager
+
+import "errors"
+
+// watchFile reports that files can only be watched with Linux inotify.
+func watchFile(path string, stop <-chan struct{}, onChange func()) error {
+	return errors.New("file watching requires Linux inotify")
+}
@@ -0,0 +1,388 @@
+// Package limits parses human-friendly resource limits and converts them to
+// the syntax of the cgroup v2 interface files.
+package limits
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Max is the value of a limit that was explicitly set to "max", removing
+// any limit the cgroup would otherwise inherit.
+const Max = math.MaxUint64
+
+// DefaultCPUPeriod is the cpu.max period, in microseconds, used for limits
+// given in cores.
+const DefaultCPUPeriod = 100000
+
+const (
+	// minCPUQuota and maxCPUQuota bound the quota the kernel accepts in
+	// cpu.max.
+	minCPUQuota = 1000
+	maxCPUQuota = 1<<44 - 1
+	// maxCPUMillicores is the largest CPU limit that gives a quota within
+	// maxCPUQuota over DefaultCPUPeriod.
+	maxCPUMillicores = maxCPUQuota / (DefaultCPUPeriod / 1000)
+	// minCPUPeriod and maxCPUPeriod bound the period accepted in cpu.max.
+	minCPUPeriod = 1000
+	maxCPUPeriod = 1000000
+	// maxCPUWeight is the largest value accepted in cpu.weight.
+	maxCPUWeight = 10000
+)
+
+// Limits are the resource limits of a job. Zero fields leave the
+// corresponding limit unset.
+type Limits struct {
+	// MemoryMax is the hard memory limit in bytes (memory.max).
+	MemoryMax uint64 `json:"memory_max,omitempty"`
+	// MemoryHigh is the memory throttling threshold in bytes (memory.high).
+	MemoryHigh uint64 `json:"memory_high,omitempty"`
+	// MemorySwapMax is the swap limit in bytes (memory.swap.max). Nil
+	// leaves it unset, zero disables swap.
+	MemorySwapMax *uint64 `json:"memory_swap_max,omitempty"`
+	// CPUQuota and CPUPeriod are the CPU bandwidth in microseconds (cpu.max).
+	CPUQuota  uint64 `json:"cpu_quota,omitempty"`
+	CPUPeriod uint64 `json:"cpu_period,omitempty"`
+	// CPUWeight is the relative CPU share between 1 and 10000 (cpu.weight).
+	CPUWeight uint64 `json:"cpu_weight,omitempty"`
+	// PidsMax is the maximum number of processes (pids.max).
+	PidsMax uint64 `json:"pids_max,omitempty"`
+	// IO are the per-device IO limits (io.max).
+	IO []IOLimit `json:"io,omitempty"`
+}
+
+// IOLimit throttles IO on one block device. Zero fields are unlimited.
+type IOLimit struct {
+	// Device selects the block device by "major:minor", device node or any
+	// path on a mounted filesystem such as its mount point. Empty selects
+	// the device backing the root filesystem. The job manager resolves it
+	// to the whole disk, since io.max only applies to whole disks.
+	Device    string `json:"device,omitempty"`
+	ReadBps   uint64 `json:"rbps,omitempty"`
+	WriteBps  uint64 `json:"wbps,omitempty"`
+	ReadIOPS  uint64 `json:"riops,omitempty"`
+	WriteIOPS uint64 `json:"wiops,omitempty"`
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l.MemoryMax == 0 && l.MemoryHigh == 0 && l.MemorySwapMax == nil &&
+		l.CPUQuota == 0 && l.CPUWeight == 0 && l.PidsMax == 0 && len(l.IO) == 0
+}
+
+// Merge returns l with the limits set in update replacing its own. IO
+// limits are matched by their device as given; fields set for a device
+// already limited replace its fields, other devices are added.
+func (l Limits) Merge(update Limits) Limits {
+	merged := l
+	set := func(dst *uint64, v uint64) {
+		if v != 0 {
+			*dst = v
+		}
+	}
+	set(&merged.MemoryMax, update.MemoryMax)
+	set(&merged.MemoryHigh, update.MemoryHigh)
+	if update.MemorySwapMax != nil {
+		swap := *update.MemorySwapMax
+		merged.MemorySwapMax = &swap
+	}
+	if update.CPUQuota != 0 {
+		merged.CPUQuota, merged.CPUPeriod = update.CPUQuota, update.CPUPeriod
+	}
+	set(&merged.CPUWeight, update.CPUWeight)
+	set(&merged.PidsMax, update.PidsMax)
+
+	merged.IO = append([]IOLimit(nil), l.IO...)
+	for _, io := range update.IO {
+		i := 0
+		for i < len(merged.IO) && merged.IO[i].Device != io.Device {
+			i++
+		}
+		if i == len(merged.IO) {
+			merged.IO = append(merged.IO, io)
+			continue
+		}
+		set(&merged.IO[i].ReadBps, io.ReadBps)
+		set(&merged.IO[i].WriteBps, io.WriteBps)
+		set(&merged.IO[i].ReadIOPS, io.ReadIOPS)
+		set(&merged.IO[i].WriteIOPS, io.WriteIOPS)
+	}
+	return merged
+}
+
+// Validate checks that the limits are accepted by the kernel.
+func (l Limits) Validate() error {
+	if l.CPUQuota != 0 {
+		if l.CPUQuota != Max && (l.CPUQuota < minCPUQuota || l.CPUQuota > maxCPUQuota) {
+			return fmt.Errorf("CPU quota must be between %d and %d microseconds", minCPUQuota, maxCPUQuota)
+		}
+		if l.CPUPeriod < minCPUPeriod || l.CPUPeriod > maxCPUPeriod {
+			return fmt.Errorf("CPU period must be between %d and %d microseconds", minCPUPeriod, maxCPUPeriod)
+		}
+	} else if l.CPUPeriod != 0 {
+		return errors.New("CPU period requires a CPU quota")
+	}
+	if l.CPUWeight > maxCPUWeight {
+		return fmt.Errorf("CPU weight must be between 1 and %d", maxCPUWeight)
+	}
+	for _, io := range l.IO {
+		if io.Device != "" && !isDeviceNumber(io.Device) && !strings.HasPrefix(io.Device, "/") {
+			return fmt.Errorf("device %q is neither an absolute path nor of the form major:minor", io.Device)
+		}
+		if io == (IOLimit{Device: io.Device}) {
+			return fmt.Errorf("IO limit for device %q sets no limit", io.Device)
+		}
+	}
+	return nil
+}
+
+// isDeviceNumber reports whether s is of the form "major:minor".
+func isDeviceNumber(s string) bool {
+	major, minor, ok := strings.Cut(s, ":")
+	if !ok {
+		return false
+	}
+	_, majorErr := strconv.ParseUint(major, 10, 32)
+	_, minorErr := strconv.ParseUint(minor, 10, 32)
+	return majorErr == nil && minorErr == nil
+}
+
+// FormatValue renders a limit value for a cgroup interface file.
+func FormatValue(v uint64) string {
+	if v == Max {
+		return "max"
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// CPUMax renders the cpu.max value, or "" when no CPU quota is set.
+func (l Limits) CPUMax() string {
+	if l.CPUQuota == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %d", FormatValue(l.CPUQuota), l.CPUPeriod)
+}
+
+// IOMax renders the io.max settings of the limit without the device.
+func (l IOLimit) IOMax() string {
+	var fields []string
+	for _, f := range []struct {
+		key   string
+		value uint64
+	}{{"rbps", l.ReadBps}, {"wbps", l.WriteBps}, {"riops", l.ReadIOPS}, {"wiops", l.WriteIOPS}} {
+		if f.value != 0 {
+			fields = append(fields, f.key+"="+FormatValue(f.value))
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// Parse converts the string forms of the memory, CPU and root device
+// bandwidth limits. The error names the offending limit.
+func Parse(memory, cpu, readBps, writeBps string) (Limits, error) {
+	var l Limits
+	var err error
+	if l.MemoryMax, err = ParseMemory(memory); err != nil {
+		return Limits{}, fmt.Errorf("invalid memory limit: %v", err)
+	}
+	if l.CPUQuota, l.CPUPeriod, err = ParseCPU(cpu); err != nil {
+		return Limits{}, fmt.Errorf("invalid CPU limit: %v", err)
+	}
+	if l.IO, err = ParseIO(nil, readBps, writeBps, "", ""); err != nil {
+		return Limits{}, err
+	}
+	return l, nil
+}
+
+// ParseIO converts the string forms of the IO limits and applies them to
+// each of devices, or to the root device when none are given. It returns
+// nil when no IO limit is set.
+func ParseIO(devices []string, readBps, writeBps, readIOPS, writeIOPS string) ([]IOLimit, error) {
+	var io IOLimit
+	var err error
+	if io.ReadBps, err = ParseBandwidth(readBps); err != nil {
+		return nil, fmt.Errorf("invalid read bandwidth limit: %v", err)
+	}
+	if io.WriteBps, err = ParseBandwidth(writeBps); err != nil {
+		return nil, fmt.Errorf("invalid write bandwidth limit: %v", err)
+	}
+	if io.ReadIOPS, err = parseCount(readIOPS, "IO operations per second"); err != nil {
+		return nil, fmt.Errorf("invalid read IOPS limit: %v", err)
+	}
+	if io.WriteIOPS, err = parseCount(writeIOPS, "IO operations per second"); err != nil {
+		return nil, fmt.Errorf("invalid write IOPS limit: %v", err)
+	}
+
+	if io == (IOLimit{}) {
+		if len(devices) > 0 {
+			return nil, errors.New("IO devices given without an IO limit")
+		}
+		return nil, nil
+	}
+	if len(devices) == 0 {
+		return []IOLimit{io}, nil
+	}
+	ios := make([]IOLimit, len(devices))
+	for i, device := range devices {
+		ios[i] = io
+		ios[i].Device = device
+	}
+	return ios, nil
+}
+
+// binaryUnits are the accepted size suffixes. As in the kernel, K, M, G and
+// T are powers of 1024; the Ki/KB/KiB spellings are accepted as synonyms.
+var binaryUnits = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// ParseSize parses a byte size such as "512Mi", "1.5G", "10MB" or "4096".
+func ParseSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.ToUpper(strings.TrimSpace(s[i:]))
+
+	unit = strings.TrimSuffix(unit, "B")
+	if len(unit) == 2 && unit[1] == 'I' {
+		unit = unit[:1]
+	}
+	multiplier, ok := binaryUnits[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("%q is not a size, use a number of bytes with an optional K, M, G or T suffix", s)
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a size: %v", s, err)
+	}
+	bytes := value * multiplier
+	if bytes < 1 || bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	return uint64(bytes), nil
+}
+
+// ParseMemory parses a memory limit in bytes. An empty string leaves the
+// limit unset and "max" returns Max.
+func ParseMemory(s string) (uint64, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "max":
+		return Max, nil
+	}
+	return ParseSize(s)
+}
+
+// ParseCPU parses a CPU limit into a cpu.max quota and period. It accepts a
+// number of cores ("0.5", "cpu=0.5", "2"), millicores ("250m") or the raw
+// cpu.max form "<quota> <period>". An empty string leaves the limit unset.
+func ParseCPU(s string) (quota, period uint64, err error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "":
+		return 0, 0, nil
+	case "max":
+		return Max, DefaultCPUPeriod, nil
+	}
+
+	if fields := strings.Fields(s); len(fields) == 2 {
+		return parseCPUMax(fields[0], fields[1])
+	}
+
+	value := strings.TrimPrefix(s, "cpu=")
+	scale := 1.0
+	if strings.HasSuffix(value, "m") {
+		value = strings.TrimSuffix(value, "m")
+		scale = 1000
+	}
+	cores, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(cores) || math.IsInf(cores, 0) {
+		return 0, 0, fmt.Errorf("%q is not a CPU limit, use cores (e.g. 0.5), millicores (e.g. 250m) or \"<quota> <period>\"", s)
+	}
+
+	millicores := math.Round(cores / scale * 1000)
+	if millicores > maxCPUMillicores {
+		return 0, 0, fmt.Errorf("%q is above the maximum of %dm", s, uint64(maxCPUMillicores))
+	}
+	quota, err = MillicoresToQuota(uint64(math.Max(0, millicores)))
+	if err != nil {
+		return 0, 0, err
+	}
+	if quota < minCPUQuota {
+		return 0, 0, fmt.Errorf("%q is below the minimum of %dm", s, minCPUQuota*1000/DefaultCPUPeriod)
+	}
+	return quota, DefaultCPUPeriod, nil
+}
+
+// MillicoresToQuota converts thousandths of a core to a cpu.max quota over
+// DefaultCPUPeriod. It fails for limits above what cpu.max accepts.
+func MillicoresToQuota(millicores uint64) (uint64, error) {
+	if millicores > maxCPUMillicores {
+		return 0, fmt.Errorf("%dm is above the maximum of %dm", millicores, uint64(maxCPUMillicores))
+	}
+	return millicores * DefaultCPUPeriod / 1000, nil
+}
+
+// parseCPUMax validates the raw "<quota> <period>" form of cpu.max.
+func parseCPUMax(quota, period string) (uint64, uint64, error) {
+	p, err := strconv.ParseUint(period, 10, 64)
+	if err != nil || p < minCPUPeriod || p > maxCPUPeriod {
+		return 0, 0, fmt.Errorf("period %q must be between %d and %d microseconds", period, minCPUPeriod, maxCPUPeriod)
+	}
+	if quota == "max" {
+		return Max, p, nil
+	}
+	q, err := strconv.ParseUint(quota, 10, 64)
+	if err != nil || q < minCPUQuota || q > maxCPUQuota {
+		return 0, 0, fmt.Errorf("quota %q must be \"max\" or between %d and %d microseconds", quota, minCPUQuota, maxCPUQuota)
+	}
+	return q, p, nil
+}
+
+// ParseBandwidth parses an IO bandwidth limit such as "10MB/s" or "1M" in
+// bytes per second. An empty string leaves the limit unset and "max"
+// returns Max.
+func ParseBandwidth(s string) (uint64, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "max":
+		return Max, nil
+	}
+	return ParseSize(strings.TrimSuffix(strings.TrimSpace(s), "/s"))
+}
+
+// ParsePids parses a limit on the number of processes. An empty string
+// leaves the limit unset and "max" returns Max.
+func ParsePids(s string) (uint64, error) {
+	return parseCount(s, "processes")
+}
+
+// parseCount parses a positive count of what. An empty string returns 0
+// and "max" returns Max.
+func parseCount(s, what string) (uint64, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "max":
+		return Max, nil
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("%q is not a positive number of %s", s, what)
+	}
+	return n, nil
+}
@@ -0,0 +1,210 @@
+package limits
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"max", Max, false},
+		{"4096", 4096, false},
+		{"512Mi", 512 << 20, false},
+		{"512M", 512 << 20, false},
+		{"512MB", 512 << 20, false},
+		{"1.5G", 1536 << 20, false},
+		{"2gib", 2 << 30, false},
+		{"100X", 0, true},
+		{"Mi", 0, true},
+		{"5i", 0, true},
+		{"-1G", 0, true},
+		{"0", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMemory(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMemory(%q) = (%d, %v), want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCPU(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"max", "max 100000", false},
+		{"0.5", "50000 100000", false},
+		{"cpu=0.5", "50000 100000", false},
+		{"2", "200000 100000", false},
+		{"250m", "25000 100000", false},
+		{"50000 100000", "50000 100000", false},
+		{"max 100000", "max 100000", false},
+		{"512", "51200000 100000", false},
+		{"5m", "", true},
+		{"half", "", true},
+		{"50000 10", "", true},
+		{"10 100000", "", true},
+		{"NaN", "", true},
+		{"1e30", "", true},
+		{"1e30m", "", true},
+		{"-1e30", "", true},
+		{"18446744073709551615 100000", "", true},
+		{"175921860", "17592186000000 100000", false},
+	}
+	for _, tt := range tests {
+		quota, period, err := ParseCPU(tt.in)
+		got := Limits{CPUQuota: quota, CPUPeriod: period}.CPUMax()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCPU(%q) = (%q, %v), want %q (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseBandwidth(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1048576", 1 << 20, false},
+		{"10MB/s", 10 << 20, false},
+		{"1M", 1 << 20, false},
+		{"fast", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBandwidth(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBandwidth(%q) = (%d, %v), want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseNamesOffendingLimit(t *testing.T) {
+	_, err := Parse("1G", "lots", "", "")
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid CPU limit") {
+		t.Fatalf("Parse() error = %v, want invalid CPU limit", err)
+	}
+
+	l, err := Parse("1G", "0.5", "1M", "2M")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := Limits{MemoryMax: 1 << 30, CPUQuota: 50000, CPUPeriod: 100000, IO: []IOLimit{{ReadBps: 1 << 20, WriteBps: 2 << 20}}}
+	if !reflect.DeepEqual(l, want) {
+		t.Fatalf("Parse() = %+v, want %+v", l, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		wantErr bool
+	}{
+		{"empty", Limits{}, false},
+		{"full", Limits{MemoryMax: 1 << 30, CPUQuota: 50000, CPUPeriod: 100000, CPUWeight: 100, PidsMax: 64, IO: []IOLimit{{Device: "259:0", WriteIOPS: 100}}}, false},
+		{"quota without period", Limits{CPUQuota: 50000}, true},
+		{"period without quota", Limits{CPUPeriod: 100000}, true},
+		{"weight out of range", Limits{CPUWeight: 20000}, true},
+		{"device path", Limits{IO: []IOLimit{{Device: "/scratch", ReadBps: 1}}}, false},
+		{"bad device", Limits{IO: []IOLimit{{Device: "sda", ReadBps: 1}}}, true},
+		{"empty io limit", Limits{IO: []IOLimit{{Device: "8:0"}}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.limits.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIOMax(t *testing.T) {
+	got := IOLimit{ReadBps: 1024, WriteIOPS: Max}.IOMax()
+	if got != "rbps=1024 wiops=max" {
+		t.Fatalf("IOMax() = %q", got)
+	}
+}
+
+func TestParsePids(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"max", Max, false},
+		{"256", 256, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"many", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePids(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePids(%q) = (%d, %v), want %d (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseIO(t *testing.T) {
+	ios, err := ParseIO([]string{"/scratch", "259:0"}, "", "10MB/s", "", "500")
+	if err != nil {
+		t.Fatalf("ParseIO() error = %v", err)
+	}
+	want := []IOLimit{
+		{Device: "/scratch", WriteBps: 10 << 20, WriteIOPS: 500},
+		{Device: "259:0", WriteBps: 10 << 20, WriteIOPS: 500},
+	}
+	if !reflect.DeepEqual(ios, want) {
+		t.Fatalf("ParseIO() = %+v, want %+v", ios, want)
+	}
+
+	if ios, err := ParseIO(nil, "", "", "", ""); err != nil || ios != nil {
+		t.Fatalf("ParseIO() without limits = (%v, %v), want nil", ios, err)
+	}
+	if _, err := ParseIO([]string{"/scratch"}, "", "", "", ""); err == nil {
+		t.Fatal("ParseIO() accepted devices without a limit")
+	}
+	if _, err := ParseIO(nil, "", "", "0", ""); err == nil || !strings.HasPrefix(err.Error(), "invalid read IOPS limit") {
+		t.Fatalf("ParseIO() error = %v, want invalid read IOPS limit", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	current := Limits{
+		MemoryMax: 1 << 30,
+		CPUQuota:  50000,
+		CPUPeriod: 100000,
+		PidsMax:   64,
+		IO:        []IOLimit{{Device: "/scratch", WriteBps: 1 << 20}},
+	}
+	update := Limits{
+		MemoryMax: 2 << 30,
+		PidsMax:   Max,
+		IO:        []IOLimit{{Device: "/scratch", WriteIOPS: 100}, {Device: "8:0", ReadBps: 1}},
+	}
+
+	got := current.Merge(update)
+	want := Limits{
+		MemoryMax: 2 << 30,
+		CPUQuota:  50000,
+		CPUPeriod: 100000,
+		PidsMax:   Max,
+		IO:        []IOLimit{{Device: "/scratch", WriteBps: 1 << 20, WriteIOPS: 100}, {Device: "8:0", ReadBps: 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Merge() = %+v, want %+v", got, want)
+	}
+	if current.IO[0].WriteIOPS != 0 {
+		t.Fatal("Merge() modified the current limits")
+	}
+}
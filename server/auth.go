@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	pb "github.com/arazmj/sentry-run/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// allMethods is the wildcard permission granting every SentryService RPC.
+	allMethods = "*"
+	// adminRole may see and control jobs owned by any user.
+	adminRole = "admin"
+)
+
+// RolesConfig is the on-disk representation of the roles file.
+//
+//	{
+//	  "roles": {"admin": ["*"], "developer": ["StartJob", "ListJobs"]},
+//	  "users": {"alice": ["admin"], "bob": ["developer"]}
+//	}
+type RolesConfig struct {
+	// Roles maps a role name to the RPC method names it may call.
+	Roles map[string][]string `json:"roles"`
+	// Users maps a client certificate CN to the roles assigned to it.
+	Users map[string][]string `json:"users"`
+}
+
+// Identity describes the authenticated caller of an RPC.
+type Identity struct {
+	Name  string
+	Roles []string
+}
+
+// IsAdmin reports whether the identity holds the admin role.
+func (id Identity) IsAdmin() bool {
+	for _, role := range id.Roles {
+		if role == adminRole {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess reports whether the identity may see or control a job owned by owner.
+func (id Identity) CanAccess(owner string) bool {
+	return id.IsAdmin() || id.Name == owner
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the caller identity stored by the authorizer.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// Authorizer checks the client certificate CN of every SentryService call
+// against the configured roles.
+type Authorizer struct {
+	roles map[string]map[string]bool
+	users map[string][]string
+}
+
+// NewAuthorizer builds an Authorizer from a roles configuration.
+func NewAuthorizer(config RolesConfig) (*Authorizer, error) {
+	a := &Authorizer{
+		roles: make(map[string]map[string]bool, len(config.Roles)),
+		users: make(map[string][]string, len(config.Users)),
+	}
+
+	for role, methods := range config.Roles {
+		allowed := make(map[string]bool, len(methods))
+		for _, method := range methods {
+			allowed[method] = true
+		}
+		a.roles[role] = allowed
+	}
+
+	for user, roles := range config.Users {
+		for _, role := range roles {
+			if _, ok := a.roles[role]; !ok {
+				return nil, fmt.Errorf("user %s references undefined role %s", user, role)
+			}
+		}
+		a.users[user] = roles
+	}
+
+	return a, nil
+}
+
+// LoadAuthorizer reads a JSON roles file and builds an Authorizer from it.
+func LoadAuthorizer(path string) (*Authorizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read roles file %s: %v", path, err)
+	}
+
+	var config RolesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse roles file %s: %v", path, err)
+	}
+
+	return NewAuthorizer(config)
+}
+
+// Allowed reports whether user may call the given RPC method name.
+func (a *Authorizer) Allowed(user, method string) bool {
+	for _, role := range a.users[user] {
+		allowed := a.roles[role]
+		if allowed[allMethods] || allowed[method] {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize resolves the caller of fullMethod and checks its permissions.
+// Calls to services other than SentryService (health, reflection) only
+// require a verified client certificate.
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	name, err := peerCommonName(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	prefix := "/" + pb.SentryService_ServiceDesc.ServiceName + "/"
+	if !strings.HasPrefix(fullMethod, prefix) {
+		return ctx, nil
+	}
+
+	method := strings.TrimPrefix(fullMethod, prefix)
+	if !a.Allowed(name, method) {
+		slog.Warn("permission denied", "user", name, "method", method)
+		return nil, status.Errorf(codes.PermissionDenied, "user %s is not allowed to call %s", name, method)
+	}
+
+	return context.WithValue(ctx, identityKey{}, Identity{Name: name, Roles: a.users[name]}), nil
+}
+
+// UnaryInterceptor returns a unary server interceptor enforcing the roles.
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a stream server interceptor enforcing the roles.
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorizedStream carries the caller identity in the stream context.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// peerCommonName extracts the CN of the verified client certificate.
+func peerCommonName(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no peer information")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", fmt.Errorf("connection is not authenticated with TLS")
+	}
+
+	if len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", fmt.Errorf("no verified client certificate")
+	}
+
+	name := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	if name == "" {
+		return "", fmt.Errorf("client certificate has an empty common name")
+	}
+	return name, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func testAuthorizer(t *testing.T) *Authorizer {
+	t.Helper()
+	a, err := NewAuthorizer(RolesConfig{
+		Roles: map[string][]string{
+			"admin":  {"*"},
+			"viewer": {"ListJobs", "StreamJobLogs"},
+		},
+		Users: map[string][]string{
+			"alice": {"admin"},
+			"bob":   {"viewer"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthorizer() error = %v", err)
+	}
+	return a
+}
+
+func peerContext(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestNewAuthorizerRejectsUndefinedRole(t *testing.T) {
+	_, err := NewAuthorizer(RolesConfig{Users: map[string][]string{"alice": {"root"}}})
+	if err == nil {
+		t.Fatal("NewAuthorizer() succeeded, want error for undefined role")
+	}
+}
+
+func TestLoadAuthorizer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.json")
+	data := `{"roles": {"viewer": ["ListJobs"]}, "users": {"bob": ["viewer"]}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := LoadAuthorizer(path)
+	if err != nil {
+		t.Fatalf("LoadAuthorizer() error = %v", err)
+	}
+	if !a.Allowed("bob", "ListJobs") || a.Allowed("bob", "KillJob") {
+		t.Fatal("loaded roles do not match file")
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	a := testAuthorizer(t)
+	tests := []struct {
+		user, method string
+		want         bool
+	}{
+		{"alice", "KillJob", true},
+		{"bob", "ListJobs", true},
+		{"bob", "KillJob", false},
+		{"mallory", "ListJobs", false},
+	}
+	for _, tt := range tests {
+		if got := a.Allowed(tt.user, tt.method); got != tt.want {
+			t.Errorf("Allowed(%q, %q) = %v, want %v", tt.user, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	interceptor := testAuthorizer(t).UnaryInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) {
+		id, ok := IdentityFromContext(ctx)
+		if !ok {
+			t.Fatal("identity missing from handler context")
+		}
+		return id.Name, nil
+	}
+
+	resp, err := interceptor(peerContext("bob"), nil, &grpc.UnaryServerInfo{FullMethod: "/sentry.SentryService/ListJobs"}, handler)
+	if err != nil || resp != "bob" {
+		t.Fatalf("allowed call = (%v, %v), want (bob, nil)", resp, err)
+	}
+
+	_, err = interceptor(peerContext("bob"), nil, &grpc.UnaryServerInfo{FullMethod: "/sentry.SentryService/KillJob"}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("denied call error = %v, want PermissionDenied", err)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/sentry.SentryService/ListJobs"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("call without peer error = %v, want Unauthenticated", err)
+	}
+}
+
+func TestUnaryInterceptorSkipsOtherServices(t *testing.T) {
+	interceptor := testAuthorizer(t).UnaryInterceptor()
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	}
+
+	if _, err := interceptor(peerContext("mallory"), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler); err != nil {
+		t.Fatalf("health check error = %v", err)
+	}
+	if !called {
+		t.Fatal("handler was not called")
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context { return f.ctx }
+
+func TestStreamInterceptor(t *testing.T) {
+	interceptor := testAuthorizer(t).StreamInterceptor()
+	var name string
+	handler := func(srv any, stream grpc.ServerStream) error {
+		id, _ := IdentityFromContext(stream.Context())
+		name = id.Name
+		return nil
+	}
+
+	info := &grpc.StreamServerInfo{FullMethod: "/sentry.SentryService/StreamJobLogs"}
+	if err := interceptor(nil, &fakeServerStream{ctx: peerContext("bob")}, info, handler); err != nil {
+		t.Fatalf("allowed stream error = %v", err)
+	}
+	if name != "bob" {
+		t.Fatalf("stream identity = %q, want bob", name)
+	}
+
+	if err := interceptor(nil, &fakeServerStream{ctx: peerContext("mallory")}, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("denied stream error = %v, want PermissionDenied", err)
+	}
+}
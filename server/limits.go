@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+
+	pb "github.com/arazmj/sentry-run/api/proto"
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+// limitsFromRequest returns the validated limits of a StartJob request,
+// taken from the typed limits or the deprecated string fields.
+func limitsFromRequest(req *pb.StartJobRequest) (limits.Limits, error) {
+	legacy := req.MemoryLimit != "" || req.CpuLimit != "" || req.ReadBps != "" || req.WriteBps != ""
+	if req.Limits == nil {
+		return limits.Parse(req.MemoryLimit, req.CpuLimit, req.ReadBps, req.WriteBps)
+	}
+	if legacy {
+		return limits.Limits{}, errors.New("limits cannot be combined with the deprecated string limit fields")
+	}
+	return fromProtoLimits(req.Limits)
+}
+
+// fromProtoLimits converts and validates typed limits.
+func fromProtoLimits(p *pb.ResourceLimits) (limits.Limits, error) {
+	l := limits.Limits{
+		MemoryMax:     p.MemoryMaxBytes,
+		MemoryHigh:    p.MemoryHighBytes,
+		MemorySwapMax: p.MemorySwapMaxBytes,
+		CPUQuota:      p.CpuQuotaUs,
+		CPUPeriod:     p.CpuPeriodUs,
+		CPUWeight:     p.CpuWeight,
+		PidsMax:       p.PidsMax,
+	}
+
+	if p.CpuMillicores != 0 {
+		if p.CpuQuotaUs != 0 || p.CpuPeriodUs != 0 {
+			return limits.Limits{}, errors.New("cpu_millicores cannot be combined with cpu_quota_us or cpu_period_us")
+		}
+		quota, err := limits.MillicoresToQuota(p.CpuMillicores)
+		if err != nil {
+			return limits.Limits{}, err
+		}
+		l.CPUQuota = quota
+	}
+	if l.CPUQuota != 0 && l.CPUPeriod == 0 {
+		l.CPUPeriod = limits.DefaultCPUPeriod
+	}
+
+	for _, io := range p.Io {
+		l.IO = append(l.IO, limits.IOLimit{
+			Device:    io.Device,
+			ReadBps:   io.Rbps,
+			WriteBps:  io.Wbps,
+			ReadIOPS:  io.Riops,
+			WriteIOPS: io.Wiops,
+		})
+	}
+
+	if err := l.Validate(); err != nil {
+		return limits.Limits{}, err
+	}
+	return l, nil
+}
+
+// toProtoLimits converts limits for JobInfo, or returns nil when none are
+// set. Values explicitly set to max are reported as unset.
+func toProtoLimits(l limits.Limits) *pb.ResourceLimits {
+	if l.IsZero() {
+		return nil
+	}
+
+	finite := func(v uint64) uint64 {
+		if v == limits.Max {
+			return 0
+		}
+		return v
+	}
+	p := &pb.ResourceLimits{
+		MemoryMaxBytes:  finite(l.MemoryMax),
+		MemoryHighBytes: finite(l.MemoryHigh),
+		CpuQuotaUs:      finite(l.CPUQuota),
+		CpuWeight:       l.CPUWeight,
+		PidsMax:         finite(l.PidsMax),
+	}
+	if l.MemorySwapMax != nil && *l.MemorySwapMax != limits.Max {
+		p.MemorySwapMaxBytes = l.MemorySwapMax
+	}
+	if p.CpuQuotaUs != 0 {
+		p.CpuPeriodUs = l.CPUPeriod
+		p.CpuMillicores = p.CpuQuotaUs * 1000 / l.CPUPeriod
+	}
+	for _, io := range l.IO {
+		p.Io = append(p.Io, &pb.DeviceIOLimit{
+			Device: io.Device,
+			Rbps:   finite(io.ReadBps),
+			Wbps:   finite(io.WriteBps),
+			Riops:  finite(io.ReadIOPS),
+			Wiops:  finite(io.WriteIOPS),
+		})
+	}
+	return p
+}
+
+// setLegacyLimits fills the deprecated string limit fields of a JobInfo.
+func setLegacyLimits(info *pb.JobInfo, l limits.Limits) {
+	if l.MemoryMax != 0 {
+		info.MemoryLimit = limits.FormatValue(l.MemoryMax)
+	}
+	info.CpuLimit = l.CPUMax()
+	for _, io := range l.IO {
+		if io.Device != "" {
+			continue
+		}
+		if io.ReadBps != 0 {
+			info.ReadBps = limits.FormatValue(io.ReadBps)
+		}
+		if io.WriteBps != 0 {
+			info.WriteBps = limits.FormatValue(io.WriteBps)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/arazmj/sentry-run/api/proto"
+	"github.com/arazmj/sentry-run/pkg/limits"
+)
+
+func TestLimitsFromRequestTyped(t *testing.T) {
+	swap := uint64(0)
+	req := &pb.StartJobRequest{Limits: &pb.ResourceLimits{
+		MemoryMaxBytes:     1 << 30,
+		MemoryHighBytes:    768 << 20,
+		MemorySwapMaxBytes: &swap,
+		CpuMillicores:      500,
+		CpuWeight:          200,
+		PidsMax:            128,
+		Io:                 []*pb.DeviceIOLimit{{Device: "259:0", Wbps: 10 << 20, Wiops: 100}},
+	}}
+	got, err := limitsFromRequest(req)
+	if err != nil {
+		t.Fatalf("limitsFromRequest() error = %v", err)
+	}
+	want := limits.Limits{
+		MemoryMax:     1 << 30,
+		MemoryHigh:    768 << 20,
+		MemorySwapMax: &swap,
+		CPUQuota:      50000,
+		CPUPeriod:     100000,
+		CPUWeight:     200,
+		PidsMax:       128,
+		IO:            []limits.IOLimit{{Device: "259:0", WriteBps: 10 << 20, WriteIOPS: 100}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("limitsFromRequest() = %+v, want %+v", got, want)
+	}
+
+	// The typed limits round-trip through JobInfo
+	back := toProtoLimits(got)
+	if back.GetCpuMillicores() != 500 || back.GetCpuQuotaUs() != 50000 || back.MemorySwapMaxBytes == nil || *back.MemorySwapMaxBytes != 0 || back.GetPidsMax() != 128 {
+		t.Fatalf("toProtoLimits() = %v", back)
+	}
+}
+
+func TestLimitsFromRequestRejects(t *testing.T) {
+	tests := map[string]*pb.StartJobRequest{
+		"typed and legacy":      {MemoryLimit: "1G", Limits: &pb.ResourceLimits{PidsMax: 10}},
+		"millicores and quota":  {Limits: &pb.ResourceLimits{CpuMillicores: 500, CpuQuotaUs: 50000}},
+		"quota below minimum":   {Limits: &pb.ResourceLimits{CpuMillicores: 1}},
+		"millicores overflow":   {Limits: &pb.ResourceLimits{CpuMillicores: 1 << 62}},
+		"quota above maximum":   {Limits: &pb.ResourceLimits{CpuQuotaUs: 1 << 50, CpuPeriodUs: 100000}},
+		"weight out of range":   {Limits: &pb.ResourceLimits{CpuWeight: 100000}},
+		"malformed device":      {Limits: &pb.ResourceLimits{Io: []*pb.DeviceIOLimit{{Device: "nvme0n1", Rbps: 1}}}},
+		"invalid legacy string": {CpuLimit: "fast"},
+	}
+	for name, req := range tests {
+		if _, err := limitsFromRequest(req); err == nil {
+			t.Errorf("%s: limitsFromRequest() succeeded, want error", name)
+		}
+	}
+}
+
+func TestToProtoLimitsEmpty(t *testing.T) {
+	if got := toProtoLimits(limits.Limits{}); got != nil {
+		t.Fatalf("toProtoLimits(empty) = %v, want nil", got)
+	}
+}
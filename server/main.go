@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	pb "github.com/arazmj/sentry-run/api/proto"
 	"github.com/arazmj/sentry-run/pkg/jobmanager"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
@@ -42,20 +49,159 @@ func buildLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stdout, opts))
 }
 
+// defaultPidsLimit is the pids.max applied to jobs that do not set their own
+// PID limit, so a fork bomb cannot exhaust the host's process table.
+const defaultPidsLimit = 1024
+
+// certDir holds the server's certificate, key and CA certificate.
+const certDir = "certs"
+
+// managerOptions builds the job manager configuration from the environment.
+func managerOptions() ([]jobmanager.Option, error) {
+	var opts []jobmanager.Option
+
+	certs, err := filepath.Abs(certDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the certificate directory: %v", err)
+	}
+	opts = append(opts, jobmanager.WithProtectedPaths(certs))
+
+	if grace := os.Getenv("SENTRY_STOP_GRACE_PERIOD"); grace != "" {
+		d, err := time.ParseDuration(grace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_STOP_GRACE_PERIOD %q: %v", grace, err)
+		}
+		opts = append(opts, jobmanager.WithStopGracePeriod(d))
+	}
+
+	if limit := os.Getenv("SENTRY_LOG_HISTORY_BYTES"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_LOG_HISTORY_BYTES %q: %v", limit, err)
+		}
+		opts = append(opts, jobmanager.WithHistoryLimit(n))
+	}
+
+	if dir := os.Getenv("SENTRY_LOG_SPILL_DIR"); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create SENTRY_LOG_SPILL_DIR %s: %v", dir, err)
+		}
+		opts = append(opts, jobmanager.WithSpillDir(dir))
+	}
+
+	if dir := os.Getenv("SENTRY_DATA_DIR"); dir != "" {
+		store, err := jobmanager.NewLogStore(filepath.Join(dir, "logs"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, jobmanager.WithLogStore(store))
+
+		registry, err := jobmanager.NewRegistry(filepath.Join(dir, "jobs"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, jobmanager.WithRegistry(registry), jobmanager.WithProtectedPaths(dir))
+	}
+
+	if interval := os.Getenv("SENTRY_CGROUP_GC_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_CGROUP_GC_INTERVAL %q: %v", interval, err)
+		}
+		opts = append(opts, jobmanager.WithCgroupGCInterval(d))
+	}
+
+	if dryRun := os.Getenv("SENTRY_CGROUP_GC_DRY_RUN"); dryRun != "" {
+		b, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_CGROUP_GC_DRY_RUN %q: %v", dryRun, err)
+		}
+		opts = append(opts, jobmanager.WithCgroupGCDryRun(b))
+	}
+
+	pidsLimit := uint64(defaultPidsLimit)
+	if limit := os.Getenv("SENTRY_DEFAULT_PIDS_LIMIT"); limit != "" {
+		n, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_DEFAULT_PIDS_LIMIT %q: %v", limit, err)
+		}
+		pidsLimit = n
+	}
+	opts = append(opts, jobmanager.WithDefaultPidsLimit(pidsLimit))
+
+	isolate := true
+	if isolation := os.Getenv("SENTRY_ISOLATION"); isolation != "" {
+		b, err := strconv.ParseBool(isolation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_ISOLATION %q: %v", isolation, err)
+		}
+		isolate = b
+	}
+	opts = append(opts, jobmanager.WithIsolation(isolate))
+
+	if subnet := os.Getenv("SENTRY_BRIDGE_SUBNET"); subnet != "" {
+		prefix, err := netip.ParsePrefix(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_BRIDGE_SUBNET %q: %v", subnet, err)
+		}
+		opts = append(opts, jobmanager.WithBridge(jobmanager.DefaultBridgeName, prefix))
+	}
+	if dns := os.Getenv("SENTRY_BRIDGE_DNS"); dns != "" {
+		servers := []netip.Addr{}
+		if dns != "none" {
+			for _, server := range strings.Split(dns, ",") {
+				addr, err := netip.ParseAddr(strings.TrimSpace(server))
+				if err != nil {
+					return nil, fmt.Errorf("invalid SENTRY_BRIDGE_DNS %q: %v", dns, err)
+				}
+				servers = append(servers, addr)
+			}
+		}
+		opts = append(opts, jobmanager.WithBridgeDNS(servers))
+	}
+
+	base, binds := os.Getenv("SENTRY_ROOTFS_BASE"), os.Getenv("SENTRY_ROOTFS_BINDS")
+	images, layers := os.Getenv("SENTRY_ROOTFS_IMAGES"), os.Getenv("SENTRY_ROOTFS_LAYERS")
+	mountRoots := os.Getenv("SENTRY_MOUNT_ROOTS")
+	if base != "" || binds != "" || images != "" || layers != "" || mountRoots != "" {
+		rootfs := jobmanager.RootfsConfig{Base: base, Images: images, Layers: layers}
+		if binds != "" {
+			rootfs.Binds = strings.Split(binds, ",")
+		}
+		if mountRoots != "" {
+			rootfs.MountRoots = strings.Split(mountRoots, ",")
+		}
+		if err := rootfs.Validate(); err != nil {
+			return nil, err
+		}
+		opts = append(opts, jobmanager.WithRootfs(rootfs))
+	}
+
+	if spec := os.Getenv("SENTRY_PSI_TRIGGER"); spec != "" {
+		trigger, err := jobmanager.ParsePressureTrigger(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SENTRY_PSI_TRIGGER: %v", err)
+		}
+		opts = append(opts, jobmanager.WithPressureTrigger(trigger))
+	}
+
+	return opts, nil
+}
+
 func main() {
 	logger := buildLogger()
 	slog.SetDefault(logger)
 	jobmanager.SetLogger(logger)
 
 	// Load server certificate and private key
-	serverCert, err := tls.LoadX509KeyPair("certs/server.crt", "certs/server.key")
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(certDir, "server.crt"), filepath.Join(certDir, "server.key"))
 	if err != nil {
 		slog.Error("failed to load server certificates", "error", err)
 		os.Exit(1)
 	}
 
 	// Load CA certificate
-	caCert, err := os.ReadFile("certs/ca.crt")
+	caCert, err := os.ReadFile(filepath.Join(certDir, "ca.crt"))
 	if err != nil {
 		slog.Error("failed to load CA certificate", "error", err)
 		os.Exit(1)
@@ -76,6 +222,16 @@ func main() {
 	}
 	creds := credentials.NewTLS(tlsConfig)
 
+	rolesPath := os.Getenv("SENTRY_ROLES")
+	if rolesPath == "" {
+		rolesPath = "roles.json"
+	}
+	authorizer, err := LoadAuthorizer(rolesPath)
+	if err != nil {
+		slog.Error("failed to load roles", "error", err)
+		os.Exit(1)
+	}
+
 	port := 50051
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
@@ -83,7 +239,26 @@ func main() {
 		os.Exit(1)
 	}
 
-	s := grpc.NewServer(grpc.Creds(creds))
+	managerOpts, err := managerOptions()
+	if err != nil {
+		slog.Error("invalid job manager configuration", "error", err)
+		os.Exit(1)
+	}
+
+	manager := jobmanager.New(managerOpts...)
+	if err := manager.Recover(); err != nil {
+		slog.Error("failed to recover jobs", "error", err)
+		os.Exit(1)
+	}
+	go manager.RunCgroupGC(context.Background())
+
+	// Metrics interceptors run first so rejected calls are counted too
+	metrics := NewMetrics(manager)
+	s := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(metrics.UnaryInterceptor(), authorizer.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamInterceptor(), authorizer.StreamInterceptor()),
+	)
 
 	healthServer := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(s, healthServer)
@@ -91,10 +266,25 @@ func main() {
 	healthServer.SetServingStatus("sentry.SentryService", grpc_health_v1.HealthCheckResponse_SERVING)
 	reflection.Register(s)
 
-	manager := jobmanager.New()
 	srv := NewServer(manager)
 	pb.RegisterSentryServiceServer(s, srv)
 
+	if addr := os.Getenv("SENTRY_METRICS_ADDR"); addr != "" {
+		metricsLis, err := net.Listen("tcp", addr)
+		if err != nil {
+			slog.Error("failed to listen for metrics", "addr", addr, "error", err)
+			os.Exit(1)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+		go func() {
+			slog.Info("metrics listening", "addr", metricsLis.Addr().String())
+			if err := http.Serve(metricsLis, mux); err != nil {
+				slog.Error("failed to serve metrics", "error", err)
+			}
+		}()
+	}
+
 	// Setup signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
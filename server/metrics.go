@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/arazmj/sentry-run/pkg/jobmanager"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics holds the Prometheus metrics of the server and its jobs.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	rpcs          *prometheus.CounterVec
+	rpcDuration   *prometheus.HistogramVec
+	activeStreams *prometheus.GaugeVec
+}
+
+// NewMetrics registers the server metrics and a collector that reads the
+// jobs' cgroups on every scrape.
+func NewMetrics(manager JobManager) *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		rpcs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sentry_rpcs_total",
+			Help: "RPCs handled, by method and status code.",
+		}, []string{"method", "code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "sentry_rpc_duration_seconds",
+			Help:    "Time to handle RPCs, by method and status code. Streams are measured until they end.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "sentry_active_streams",
+			Help: "Streaming RPCs in progress, by method.",
+		}, []string{"method"}),
+	}
+	m.Registry.MustRegister(
+		m.rpcs,
+		m.rpcDuration,
+		m.activeStreams,
+		&jobCollector{manager: manager},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// methodName returns the method of a full gRPC method name such as
+// "/sentry.SentryService/StartJob".
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+func (m *Metrics) observe(fullMethod string, start time.Time, err error) {
+	method, code := methodName(fullMethod), status.Code(err).String()
+	m.rpcs.WithLabelValues(method, code).Inc()
+	m.rpcDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+// UnaryInterceptor counts and times unary RPCs.
+func (m *Metrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor counts and times streaming RPCs and tracks those in
+// progress.
+func (m *Metrics) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		active := m.activeStreams.WithLabelValues(methodName(info.FullMethod))
+		active.Inc()
+		defer active.Dec()
+
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+var (
+	jobsDesc = prometheus.NewDesc("sentry_jobs",
+		"Jobs known to the server, by state.", []string{"state"}, nil)
+	jobCPUDesc = prometheus.NewDesc("sentry_job_cpu_seconds_total",
+		"CPU time consumed by a running job.", []string{"job_id", "owner"}, nil)
+	jobMemoryDesc = prometheus.NewDesc("sentry_job_memory_bytes",
+		"Memory currently used by a running job.", []string{"job_id", "owner"}, nil)
+	jobMemoryPeakDesc = prometheus.NewDesc("sentry_job_memory_peak_bytes",
+		"Peak memory used by a running job, on kernels with memory.peak.", []string{"job_id", "owner"}, nil)
+	jobIOReadDesc = prometheus.NewDesc("sentry_job_io_read_bytes_total",
+		"Bytes read by a running job, by device.", []string{"job_id", "owner", "device"}, nil)
+	jobIOWriteDesc = prometheus.NewDesc("sentry_job_io_write_bytes_total",
+		"Bytes written by a running job, by device.", []string{"job_id", "owner", "device"}, nil)
+	jobOOMKillsDesc = prometheus.NewDesc("sentry_job_oom_kills_total",
+		"Processes of a running job killed by the OOM killer.", []string{"job_id", "owner"}, nil)
+	jobPressureDesc = prometheus.NewDesc("sentry_job_pressure_ratio",
+		"Share of time a running job was stalled on a resource, averaged over a window. kind is some (at least one task stalled) or full (all tasks stalled).",
+		[]string{"job_id", "owner", "resource", "kind", "window"}, nil)
+	jobPressureStalledDesc = prometheus.NewDesc("sentry_job_pressure_stalled_seconds_total",
+		"Time a running job was stalled on a resource.", []string{"job_id", "owner", "resource", "kind"}, nil)
+	jobStallsDesc = prometheus.NewDesc("sentry_job_stall_events_total",
+		"Times the pressure trigger fired for a running job, by resource.", []string{"job_id", "owner", "resource"}, nil)
+)
+
+// jobCollector exports the job counts and the cgroup usage of running jobs.
+// Finished jobs have no cgroup left, so their series disappear.
+type jobCollector struct {
+	manager JobManager
+}
+
+func (c *jobCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{jobsDesc, jobCPUDesc, jobMemoryDesc, jobMemoryPeakDesc, jobIOReadDesc, jobIOWriteDesc, jobOOMKillsDesc,
+		jobPressureDesc, jobPressureStalledDesc, jobStallsDesc} {
+		ch <- desc
+	}
+}
+
+func (c *jobCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := make(map[jobmanager.State]int)
+	for state := range protoStates {
+		counts[state] = 0
+	}
+
+	for _, job := range c.manager.ListJobs() {
+		jobStatus, err := c.manager.GetJobStatus(job.ID)
+		if err != nil {
+			continue
+		}
+		counts[jobStatus.State]++
+		if !jobStatus.IsRunning() {
+			continue
+		}
+
+		stats, err := c.manager.GetJobStats(job.ID)
+		if err != nil {
+			// Jobs without limits have no cgroup to read
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(jobCPUDesc, prometheus.CounterValue, float64(stats.CPU.UsageUsec)/1e6, job.ID, job.Owner)
+		ch <- prometheus.MustNewConstMetric(jobMemoryDesc, prometheus.GaugeValue, float64(stats.Memory.Current), job.ID, job.Owner)
+		if stats.Memory.Peak > 0 {
+			ch <- prometheus.MustNewConstMetric(jobMemoryPeakDesc, prometheus.GaugeValue, float64(stats.Memory.Peak), job.ID, job.Owner)
+		}
+		for _, io := range stats.IO {
+			ch <- prometheus.MustNewConstMetric(jobIOReadDesc, prometheus.CounterValue, float64(io.ReadBytes), job.ID, job.Owner, io.Device)
+			ch <- prometheus.MustNewConstMetric(jobIOWriteDesc, prometheus.CounterValue, float64(io.WriteBytes), job.ID, job.Owner, io.Device)
+		}
+		ch <- prometheus.MustNewConstMetric(jobOOMKillsDesc, prometheus.CounterValue, float64(stats.Memory.Events.OOMKill), job.ID, job.Owner)
+
+		for _, resource := range []struct {
+			name     string
+			pressure jobmanager.Pressure
+			stalls   uint64
+		}{
+			{jobmanager.PressureCPU, stats.Pressure.CPU, jobStatus.Stalls.CPU},
+			{jobmanager.PressureMemory, stats.Pressure.Memory, jobStatus.Stalls.Memory},
+			{jobmanager.PressureIO, stats.Pressure.IO, jobStatus.Stalls.IO},
+		} {
+			collectPressure(ch, job, resource.name, "some", resource.pressure.Some)
+			collectPressure(ch, job, resource.name, "full", resource.pressure.Full)
+			ch <- prometheus.MustNewConstMetric(jobStallsDesc, prometheus.CounterValue, float64(resource.stalls), job.ID, job.Owner, resource.name)
+		}
+	}
+
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(jobsDesc, prometheus.GaugeValue, float64(count), string(state))
+	}
+}
+
+// collectPressure exports one line of a pressure file. The kernel reports
+// percentages, exported as ratios.
+func collectPressure(ch chan<- prometheus.Metric, job *jobmanager.Job, resource, kind string, line jobmanager.PressureLine) {
+	for window, avg := range map[string]float64{"10s": line.Avg10, "60s": line.Avg60, "300s": line.Avg300} {
+		ch <- prometheus.MustNewConstMetric(jobPressureDesc, prometheus.GaugeValue, avg/100, job.ID, job.Owner, resource, kind, window)
+	}
+	ch <- prometheus.MustNewConstMetric(jobPressureStalledDesc, prometheus.CounterValue, float64(line.TotalUsec)/1e6, job.ID, job.Owner, resource, kind)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arazmj/sentry-run/pkg/jobmanager"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsCountRPCs(t *testing.T) {
+	metrics := NewMetrics(&fakeJobManager{})
+	interceptor := metrics.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/sentry.SentryService/StartJob"}
+
+	ok := func(ctx context.Context, req any) (any, error) { return nil, nil }
+	denied := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+	interceptor(context.Background(), nil, info, ok)
+	interceptor(context.Background(), nil, info, ok)
+	interceptor(context.Background(), nil, info, denied)
+
+	if got := testutil.ToFloat64(metrics.rpcs.WithLabelValues("StartJob", "OK")); got != 2 {
+		t.Errorf("OK StartJob calls = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(metrics.rpcs.WithLabelValues("StartJob", "PermissionDenied")); got != 1 {
+		t.Errorf("denied StartJob calls = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(metrics.rpcDuration); got != 2 {
+		t.Errorf("duration series = %d, want 2", got)
+	}
+}
+
+func TestMetricsTrackActiveStreams(t *testing.T) {
+	metrics := NewMetrics(&fakeJobManager{})
+	info := &grpc.StreamServerInfo{FullMethod: "/sentry.SentryService/StreamJobLogs"}
+	active := metrics.activeStreams.WithLabelValues("StreamJobLogs")
+
+	err := metrics.StreamInterceptor()(nil, nil, info, func(srv any, stream grpc.ServerStream) error {
+		if got := testutil.ToFloat64(active); got != 1 {
+			t.Errorf("active streams while streaming = %v, want 1", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(active); got != 0 {
+		t.Errorf("active streams after the stream = %v, want 0", got)
+	}
+}
+
+func TestJobCollector(t *testing.T) {
+	fake := &fakeJobManager{
+		jobs: []*jobmanager.Job{{ID: "job-1", Owner: "alice"}, {ID: "job-2", Owner: "bob"}},
+		status: map[string]jobmanager.Status{
+			"job-1": {State: jobmanager.StateRunning},
+			"job-2": {State: jobmanager.StateExited},
+		},
+		stats: jobmanager.Stats{
+			CPU:    jobmanager.CPUStats{UsageUsec: 1500000},
+			Memory: jobmanager.MemoryStats{Current: 4096, Events: jobmanager.MemoryEvents{OOMKill: 1}},
+			IO:     []jobmanager.IOStats{{Device: "259:0", ReadBytes: 100, WriteBytes: 200}},
+		},
+	}
+
+	want := `
+# HELP sentry_job_cpu_seconds_total CPU time consumed by a running job.
+# TYPE sentry_job_cpu_seconds_total counter
+sentry_job_cpu_seconds_total{job_id="job-1",owner="alice"} 1.5
+# HELP sentry_job_io_write_bytes_total Bytes written by a running job, by device.
+# TYPE sentry_job_io_write_bytes_total counter
+sentry_job_io_write_bytes_total{device="259:0",job_id="job-1",owner="alice"} 200
+# HELP sentry_job_memory_bytes Memory currently used by a running job.
+# TYPE sentry_job_memory_bytes gauge
+sentry_job_memory_bytes{job_id="job-1",owner="alice"} 4096
+# HELP sentry_job_oom_kills_total Processes of a running job killed by the OOM killer.
+# TYPE sentry_job_oom_kills_total counter
+sentry_job_oom_kills_total{job_id="job-1",owner="alice"} 1
+# HELP sentry_jobs Jobs known to the server, by state.
+# TYPE sentry_jobs gauge
+sentry_jobs{state="exited"} 1
+sentry_jobs{state="failed-to-start"} 0
+sentry_jobs{state="killed"} 0
+sentry_jobs{state="lost"} 0
+sentry_jobs{state="oom-killed"} 0
+sentry_jobs{state="pending"} 0
+sentry_jobs{state="running"} 1
+`
+	collector := &jobCollector{manager: fake}
+	names := []string{"sentry_job_cpu_seconds_total", "sentry_job_io_write_bytes_total", "sentry_job_memory_bytes", "sentry_job_oom_kills_total", "sentry_jobs"}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want), names...); err != nil {
+		t.Fatal(err)
+	}
+
+	// A running job without a cgroup still counts, but has no usage series
+	fake.statsErr = errors.New("no cgroup")
+	if got := testutil.CollectAndCount(collector, "sentry_job_memory_bytes"); got != 0 {
+		t.Fatalf("memory series = %d, want 0", got)
+	}
+}
+
+func TestJobCollectorPressure(t *testing.T) {
+	fake := &fakeJobManager{
+		jobs:   []*jobmanager.Job{{ID: "job-1", Owner: "alice"}},
+		status: map[string]jobmanager.Status{"job-1": {State: jobmanager.StateRunning, Stalls: jobmanager.Stalls{Memory: 2}}},
+		stats: jobmanager.Stats{Pressure: jobmanager.JobPressure{
+			Memory: jobmanager.Pressure{Some: jobmanager.PressureLine{Avg10: 12.5, TotalUsec: 2500000}},
+		}},
+	}
+
+	want := `
+# HELP sentry_job_pressure_stalled_seconds_total Time a running job was stalled on a resource.
+# TYPE sentry_job_pressure_stalled_seconds_total counter
+sentry_job_pressure_stalled_seconds_total{job_id="job-1",kind="full",owner="alice",resource="cpu"} 0
+sentry_job_pressure_stalled_seconds_total{job_id="job-1",kind="full",owner="alice",resource="io"} 0
+sentry_job_pressure_stalled_seconds_total{job_id="job-1",kind="full",owner="alice",resource="memory"} 0
+sentry_job_pressure_stalled_seconds_total{job_id="job-1",kind="some",owner="alice",resource="cpu"} 0
+sentry_job_pressure_stalled_seconds_total{job_id="job-1",kind="some",owner="alice",resource="io"} 0
+sentry_job_pressure_stalled_seconds_total{job_id="job-1",kind="some",owner="alice",resource="memory"} 2.5
+# HELP sentry_job_stall_events_total Times the pressure trigger fired for a running job, by resource.
+# TYPE sentry_job_stall_events_total counter
+sentry_job_stall_events_total{job_id="job-1",owner="alice",resource="cpu"} 0
+sentry_job_stall_events_total{job_id="job-1",owner="alice",resource="io"} 0
+sentry_job_stall_events_total{job_id="job-1",owner="alice",resource="memory"} 2
+`
+	collector := &jobCollector{manager: fake}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "sentry_job_pressure_stalled_seconds_total", "sentry_job_stall_events_total"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Three resources, some and full, over three windows
+	if got := testutil.CollectAndCount(collector, "sentry_job_pressure_ratio"); got != 18 {
+		t.Fatalf("pressure series = %d, want 18", got)
+	}
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "sentry_job_pressure_ratio" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["resource"] == "memory" && labels["kind"] == "some" && labels["window"] == "10s" && metric.GetGauge().GetValue() != 0.125 {
+				t.Errorf("memory some avg10 = %v, want 0.125", metric.GetGauge().GetValue())
+			}
+		}
+	}
+}
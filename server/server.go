@@ -1,24 +1,50 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"time"
 
 	pb "github.com/arazmj/sentry-run/api/proto"
 	"github.com/arazmj/sentry-run/pkg/jobmanager"
+	"github.com/arazmj/sentry-run/pkg/limits"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type JobManager interface {
-	StartJob(command string, commandArgs []string, memoryLimit, cpuLimit, mount, writeBps, readBps string) (*jobmanager.Job, error)
-	StopJob(jobID string) error
+	StartJob(owner, command string, commandArgs []string, jobLimits limits.Limits, opts jobmanager.StartOptions) (*jobmanager.Job, error)
+	StopJob(jobID string, gracePeriod time.Duration) (jobmanager.StopResult, error)
 	KillJob(jobID string) error
-	GetJobStatus(jobID string) (bool, error)
-	GetJobOutput(jobID string) (stdout, stderr []byte, err error)
+	GetJob(jobID string) (*jobmanager.Job, error)
+	GetJobStatus(jobID string) (jobmanager.Status, error)
+	GetJobPids(jobID string) (uint64, error)
+	UpdateJobLimits(jobID string, update limits.Limits) (limits.Limits, error)
+	GetJobStats(jobID string) (jobmanager.Stats, error)
+	GetJobPressure(jobID string) (jobmanager.JobPressure, error)
+	WaitJob(ctx context.Context, jobID string) (jobmanager.Status, error)
+	OpenJobOutput(jobID string) (stdout, stderr *jobmanager.OutputReader, err error)
 	ListJobs() []*jobmanager.Job
+	RemoveJob(jobID string) error
+	PruneJobs(filter jobmanager.PruneFilter) []string
+	CollectOrphanedCgroups() jobmanager.CgroupGCReport
+	LastCgroupGCReport() (jobmanager.CgroupGCReport, bool)
 	StreamOutput(ctx context.Context, jobID string, callback jobmanager.OutputCallback) error
 }
 
+const (
+	// logChunkSize bounds the output sent in one JobOutput message.
+	logChunkSize = 64 << 10
+	// logsTailLimit bounds the output of each stream returned by
+	// GetJobLogs, which answers with a single message.
+	logsTailLimit = 1 << 20
+)
+
 type Server struct {
 	pb.UnimplementedSentryServiceServer
 	manager JobManager
@@ -28,8 +54,169 @@ func NewServer(manager JobManager) *Server {
 	return &Server{manager: manager}
 }
 
+var protoStates = map[jobmanager.State]pb.JobState{
+	jobmanager.StatePending:       pb.JobState_JOB_STATE_PENDING,
+	jobmanager.StateRunning:       pb.JobState_JOB_STATE_RUNNING,
+	jobmanager.StateExited:        pb.JobState_JOB_STATE_EXITED,
+	jobmanager.StateKilled:        pb.JobState_JOB_STATE_KILLED,
+	jobmanager.StateFailedToStart: pb.JobState_JOB_STATE_FAILED_TO_START,
+	jobmanager.StateOOMKilled:     pb.JobState_JOB_STATE_OOM_KILLED,
+	jobmanager.StateLost:          pb.JobState_JOB_STATE_LOST,
+}
+
+func toProtoState(state jobmanager.State) pb.JobState {
+	return protoStates[state]
+}
+
+func fromProtoState(state pb.JobState) (jobmanager.State, bool) {
+	for s, p := range protoStates {
+		if p == state {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func toProtoTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toJobStatusResponse(jobStatus jobmanager.Status) *pb.JobStatusResponse {
+	return &pb.JobStatusResponse{
+		IsRunning:  jobStatus.IsRunning(),
+		State:      toProtoState(jobStatus.State),
+		ExitCode:   int32(jobStatus.ExitCode),
+		Signal:     int32(jobStatus.Signal),
+		StartedAt:  toProtoTime(jobStatus.StartedAt),
+		FinishedAt: toProtoTime(jobStatus.FinishedAt),
+		Error:      jobStatus.Error,
+		OomEvents:  jobStatus.OOMEvents,
+		OomKills:   jobStatus.OOMKills,
+		Stalls: &pb.StallCounts{
+			Cpu:    jobStatus.Stalls.CPU,
+			Memory: jobStatus.Stalls.Memory,
+			Io:     jobStatus.Stalls.IO,
+		},
+	}
+}
+
+func toProtoPressureLine(line jobmanager.PressureLine) *pb.PressureLine {
+	return &pb.PressureLine{
+		Avg10:     line.Avg10,
+		Avg60:     line.Avg60,
+		Avg300:    line.Avg300,
+		TotalUsec: line.TotalUsec,
+	}
+}
+
+func toProtoPressure(pressure jobmanager.JobPressure) *pb.JobPressure {
+	resource := func(p jobmanager.Pressure) *pb.Pressure {
+		return &pb.Pressure{Some: toProtoPressureLine(p.Some), Full: toProtoPressureLine(p.Full)}
+	}
+	return &pb.JobPressure{
+		Cpu:    resource(pressure.CPU),
+		Memory: resource(pressure.Memory),
+		Io:     resource(pressure.IO),
+	}
+}
+
+func toJobStatsResponse(stats jobmanager.Stats) *pb.JobStatsResponse {
+	resp := &pb.JobStatsResponse{
+		CollectedAt: toProtoTime(stats.CollectedAt),
+		Cpu: &pb.CPUStats{
+			UsageUsec:     stats.CPU.UsageUsec,
+			UserUsec:      stats.CPU.UserUsec,
+			SystemUsec:    stats.CPU.SystemUsec,
+			NrPeriods:     stats.CPU.NrPeriods,
+			NrThrottled:   stats.CPU.NrThrottled,
+			ThrottledUsec: stats.CPU.ThrottledUsec,
+		},
+		Memory: &pb.MemoryStats{
+			CurrentBytes:  stats.Memory.Current,
+			PeakBytes:     stats.Memory.Peak,
+			AnonBytes:     stats.Memory.Anon,
+			FileBytes:     stats.Memory.File,
+			Stat:          stats.Memory.Stat,
+			EventsLow:     stats.Memory.Events.Low,
+			EventsHigh:    stats.Memory.Events.High,
+			EventsMax:     stats.Memory.Events.Max,
+			EventsOom:     stats.Memory.Events.OOM,
+			EventsOomKill: stats.Memory.Events.OOMKill,
+		},
+		PidsCurrent: stats.PidsCurrent,
+		Pressure:    toProtoPressure(stats.Pressure),
+	}
+	for _, io := range stats.IO {
+		resp.Io = append(resp.Io, &pb.IOStats{
+			Device:       io.Device,
+			ReadBytes:    io.ReadBytes,
+			WriteBytes:   io.WriteBytes,
+			ReadIos:      io.ReadIOs,
+			WriteIos:     io.WriteIOs,
+			DiscardBytes: io.DiscardBytes,
+			DiscardIos:   io.DiscardIOs,
+		})
+	}
+	return resp
+}
+
+// callerIdentity returns the authenticated caller stored by the authorizer.
+func callerIdentity(ctx context.Context) (Identity, error) {
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return Identity{}, status.Error(codes.Unauthenticated, "missing caller identity")
+	}
+	return id, nil
+}
+
+// authorizeJob checks that the caller owns the job or holds the admin role.
+func (s *Server) authorizeJob(ctx context.Context, jobID string) error {
+	id, err := callerIdentity(ctx)
+	if err != nil {
+		return err
+	}
+
+	job, err := s.manager.GetJob(jobID)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	if !id.CanAccess(job.Owner) {
+		slog.Warn("job access denied", "user", id.Name, "job_id", jobID, "owner", job.Owner)
+		return status.Errorf(codes.PermissionDenied, "job %s is not owned by %s", jobID, id.Name)
+	}
+	return nil
+}
+
 func (s *Server) StartJob(ctx context.Context, req *pb.StartJobRequest) (*pb.StartJobResponse, error) {
-	job, err := s.manager.StartJob(req.Command, req.CommandArgs, req.MemoryLimit, req.CpuLimit, req.Mount, req.WriteBps, req.ReadBps)
+	id, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jobLimits, err := limitsFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	network, err := jobmanager.ParseNetworkMode(req.Network)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	job, err := s.manager.StartJob(id.Name, req.Command, req.CommandArgs, jobLimits, jobmanager.StartOptions{
+		Mount:      req.Mount,
+		Network:    network,
+		Image:      req.Image,
+		KeepRootfs: req.KeepRootfs,
+	})
+	var invalid *jobmanager.InvalidOptionsError
+	if errors.As(err, &invalid) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		slog.Error("failed to start job", "error", err)
 		return nil, err
@@ -40,24 +227,47 @@ func (s *Server) StartJob(ctx context.Context, req *pb.StartJobRequest) (*pb.Sta
 }
 
 func (s *Server) StopJob(ctx context.Context, req *pb.StopJobRequest) (*pb.StopJobResponse, error) {
-	err := s.manager.StopJob(req.JobId)
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	var gracePeriod time.Duration
+	if req.GracePeriod != nil {
+		if err := req.GracePeriod.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid grace period: %v", err)
+		}
+		gracePeriod = req.GracePeriod.AsDuration()
+	}
+
+	result, err := s.manager.StopJob(req.JobId, gracePeriod)
 	if err != nil {
 		slog.Error("failed to stop job", "job_id", req.JobId, "error", err)
 		return &pb.StopJobResponse{
-			Success: false,
-			Message: err.Error(),
+			Success:   false,
+			Message:   err.Error(),
+			Escalated: result.Escalated,
 		}, nil
 	}
 
-	slog.Info("stopped job", "job_id", req.JobId)
+	slog.Info("stopped job", "job_id", req.JobId, "escalated", result.Escalated, "state", result.Status.State)
+	message := fmt.Sprintf("Job %s stopped successfully with SIGTERM", req.JobId)
+	if result.Escalated {
+		message = fmt.Sprintf("Job %s ignored SIGTERM and was killed with SIGKILL", req.JobId)
+	}
 	return &pb.StopJobResponse{
-		Success: true,
-		Message: fmt.Sprintf("Job %s stopped successfully", req.JobId),
+		Success:   true,
+		Message:   message,
+		Escalated: result.Escalated,
+		State:     toProtoState(result.Status.State),
 	}, nil
 }
 
 func (s *Server) GetJobStatus(ctx context.Context, req *pb.JobStatusRequest) (*pb.JobStatusResponse, error) {
-	isRunning, err := s.manager.GetJobStatus(req.JobId)
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	jobStatus, err := s.manager.GetJobStatus(req.JobId)
 	if err != nil {
 		slog.Error("failed to get job status", "job_id", req.JobId, "error", err)
 		return &pb.JobStatusResponse{
@@ -65,42 +275,166 @@ func (s *Server) GetJobStatus(ctx context.Context, req *pb.JobStatusRequest) (*p
 		}, nil
 	}
 
-	return &pb.JobStatusResponse{
-		IsRunning: isRunning,
-	}, nil
+	resp := toJobStatusResponse(jobStatus)
+	if job, err := s.manager.GetJob(req.JobId); err == nil {
+		if pidsMax := job.ResourceLimits().PidsMax; pidsMax != limits.Max {
+			resp.PidsMax = pidsMax
+		}
+	}
+	if jobStatus.IsRunning() {
+		pids, err := s.manager.GetJobPids(req.JobId)
+		if err != nil {
+			slog.Warn("failed to read job process count", "job_id", req.JobId, "error", err)
+		}
+		resp.PidsCurrent = pids
+
+		pressure, err := s.manager.GetJobPressure(req.JobId)
+		if err != nil {
+			slog.Warn("failed to read job pressure", "job_id", req.JobId, "error", err)
+		} else {
+			resp.Pressure = toProtoPressure(pressure)
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) WaitJob(ctx context.Context, req *pb.WaitJobRequest) (*pb.JobStatusResponse, error) {
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	if req.Timeout != nil {
+		if err := req.Timeout.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid timeout: %v", err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout.AsDuration())
+		defer cancel()
+	}
+
+	jobStatus, err := s.manager.WaitJob(ctx, req.JobId)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, status.Errorf(codes.DeadlineExceeded, "job %s is still %s", req.JobId, jobStatus.State)
+		}
+		if errors.Is(err, context.Canceled) {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return toJobStatusResponse(jobStatus), nil
 }
 
 func (s *Server) GetJobLogs(ctx context.Context, req *pb.JobLogsRequest) (*pb.JobLogsResponse, error) {
-	stdout, stderr, err := s.manager.GetJobOutput(req.JobId)
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, err := s.manager.OpenJobOutput(req.JobId)
 	if err != nil {
 		return nil, err
 	}
+	defer stdout.Close()
+	defer stderr.Close()
 
 	// Combine stdout and stderr with markers
-	logs := fmt.Sprintf("=== STDOUT ===\n%s\n=== STDERR ===\n%s", stdout, stderr)
+	var logs bytes.Buffer
+	if err := writeLogsTail(&logs, "STDOUT", stdout); err != nil {
+		return nil, err
+	}
+	logs.WriteString("\n")
+	if err := writeLogsTail(&logs, "STDERR", stderr); err != nil {
+		return nil, err
+	}
 	return &pb.JobLogsResponse{
-		Logs: []byte(logs),
+		Logs: logs.Bytes(),
 	}, nil
 }
 
+// writeLogsTail writes the last logsTailLimit bytes of a stream under a
+// marker that says when older output was left out.
+func writeLogsTail(logs *bytes.Buffer, name string, r *jobmanager.OutputReader) error {
+	if size := r.Size(); size > logsTailLimit {
+		if _, err := r.Seek(-logsTailLimit, io.SeekEnd); err != nil {
+			return err
+		}
+		fmt.Fprintf(logs, "=== %s (last %d of %d bytes, stream the logs for all) ===\n", name, logsTailLimit, size)
+	} else {
+		fmt.Fprintf(logs, "=== %s ===\n", name)
+	}
+	if _, err := io.Copy(logs, r); err != nil {
+		return fmt.Errorf("failed to read job output: %v", err)
+	}
+	return nil
+}
+
+// sendOutput streams the output of r in messages of at most logChunkSize
+// bytes.
+func sendOutput(stream pb.SentryService_StreamJobLogsServer, jobID string, r *jobmanager.OutputReader, isStderr bool) error {
+	buf := make([]byte, logChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&pb.JobOutput{JobId: jobID, Data: buf[:n], IsStderr: isStderr}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read job output: %v", err)
+		}
+	}
+}
+
 func (s *Server) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	id, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	jobs := s.manager.ListJobs()
 	response := &pb.ListJobsResponse{
 		Jobs: make([]*pb.JobInfo, 0, len(jobs)),
 	}
 
 	for _, job := range jobs {
-		isRunning, _ := s.manager.GetJobStatus(job.ID)
+		if !id.CanAccess(job.Owner) {
+			continue
+		}
+
+		jobStatus, _ := s.manager.GetJobStatus(job.ID)
+		jobLimits := job.ResourceLimits()
 		jobInfo := &pb.JobInfo{
-			JobId:       job.ID,
-			Command:     job.Command,
-			IsRunning:   isRunning,
-			MemoryLimit: job.MemoryLimit,
-			CpuLimit:    job.CpuLimit,
-			Mount:       job.Mount,
-			ReadBps:     job.ReadBps,
-			WriteBps:    job.WriteBps,
+			JobId:      job.ID,
+			Owner:      job.Owner,
+			Command:    job.Command,
+			IsRunning:  jobStatus.IsRunning(),
+			State:      toProtoState(jobStatus.State),
+			ExitCode:   int32(jobStatus.ExitCode),
+			Signal:     int32(jobStatus.Signal),
+			StartedAt:  toProtoTime(jobStatus.StartedAt),
+			FinishedAt: toProtoTime(jobStatus.FinishedAt),
+			Mount:      job.Mount,
+			DeviceId:   job.Devices(),
+			Limits:     toProtoLimits(jobLimits),
+			Isolated:   job.Isolated,
+			Network:    string(job.Network),
+			IpAddress:  job.Address,
+			Image:      job.Image,
+			UpperDir:   job.UpperDir(),
+			KeepRootfs: job.KeepRootfs,
+		}
+		for _, change := range job.LimitChanges() {
+			jobInfo.LimitChanges = append(jobInfo.LimitChanges, &pb.LimitChange{
+				ChangedAt: toProtoTime(change.ChangedAt),
+				Previous:  toProtoLimits(change.Previous),
+				Limits:    toProtoLimits(change.Limits),
+			})
 		}
+		setLegacyLimits(jobInfo, jobLimits)
 		response.Jobs = append(response.Jobs, jobInfo)
 	}
 
@@ -108,6 +442,10 @@ func (s *Server) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.Lis
 }
 
 func (s *Server) KillJob(ctx context.Context, req *pb.KillJobRequest) (*pb.KillJobResponse, error) {
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
 	err := s.manager.KillJob(req.JobId)
 	if err != nil {
 		return &pb.KillJobResponse{
@@ -123,32 +461,26 @@ func (s *Server) KillJob(ctx context.Context, req *pb.KillJobRequest) (*pb.KillJ
 }
 
 func (s *Server) StreamJobLogs(req *pb.JobLogsRequest, stream pb.SentryService_StreamJobLogsServer) error {
+	if err := s.authorizeJob(stream.Context(), req.JobId); err != nil {
+		return err
+	}
+
 	// First, send existing logs
-	stdout, stderr, err := s.manager.GetJobOutput(req.JobId)
+	stdout, stderr, err := s.manager.OpenJobOutput(req.JobId)
 	if err != nil {
 		return err
 	}
+	defer stdout.Close()
+	defer stderr.Close()
 
-	// Send stdout history
-	if len(stdout) > 0 {
-		if err := stream.Send(&pb.JobOutput{
-			JobId:    req.JobId,
-			Data:     stdout,
-			IsStderr: false,
-		}); err != nil {
-			return err
-		}
+	if err := sendOutput(stream, req.JobId, stdout, false); err != nil {
+		return err
 	}
-
-	// Send stderr history
-	if len(stderr) > 0 {
-		if err := stream.Send(&pb.JobOutput{
-			JobId:    req.JobId,
-			Data:     stderr,
-			IsStderr: true,
-		}); err != nil {
-			return err
-		}
+	if err := sendOutput(stream, req.JobId, stderr, true); err != nil {
+		return err
+	}
+	if req.HistoryOnly {
+		return nil
 	}
 
 	ctx := stream.Context()
@@ -162,3 +494,137 @@ func (s *Server) StreamJobLogs(req *pb.JobLogsRequest, stream pb.SentryService_S
 		})
 	})
 }
+
+func (s *Server) UpdateJobLimits(ctx context.Context, req *pb.UpdateJobLimitsRequest) (*pb.UpdateJobLimitsResponse, error) {
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	if req.Limits == nil {
+		return nil, status.Error(codes.InvalidArgument, "limits are required")
+	}
+	update, err := fromProtoLimits(req.Limits)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	jobLimits, err := s.manager.UpdateJobLimits(req.JobId, update)
+	if err != nil {
+		return &pb.UpdateJobLimitsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	slog.Info("updated job limits", "job_id", req.JobId)
+	return &pb.UpdateJobLimitsResponse{
+		Success: true,
+		Message: fmt.Sprintf("Job %s limits updated successfully", req.JobId),
+		Limits:  toProtoLimits(jobLimits),
+	}, nil
+}
+
+func (s *Server) GetJobStats(ctx context.Context, req *pb.JobStatsRequest) (*pb.JobStatsResponse, error) {
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.manager.GetJobStats(req.JobId)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return toJobStatsResponse(stats), nil
+}
+
+func (s *Server) RemoveJob(ctx context.Context, req *pb.RemoveJobRequest) (*pb.RemoveJobResponse, error) {
+	if err := s.authorizeJob(ctx, req.JobId); err != nil {
+		return nil, err
+	}
+
+	if err := s.manager.RemoveJob(req.JobId); err != nil {
+		return &pb.RemoveJobResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	slog.Info("removed job", "job_id", req.JobId)
+	return &pb.RemoveJobResponse{
+		Success: true,
+		Message: fmt.Sprintf("Job %s removed successfully", req.JobId),
+	}, nil
+}
+
+func (s *Server) PruneJobs(ctx context.Context, req *pb.PruneJobsRequest) (*pb.PruneJobsResponse, error) {
+	id, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter jobmanager.PruneFilter
+	if !id.IsAdmin() {
+		filter.Owner = id.Name
+	}
+	if req.OlderThan != nil {
+		if err := req.OlderThan.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid age: %v", err)
+		}
+		filter.OlderThan = req.OlderThan.AsDuration()
+	}
+	for _, protoState := range req.States {
+		state, ok := fromProtoState(protoState)
+		if !ok || !state.Finished() {
+			return nil, status.Errorf(codes.InvalidArgument, "cannot prune jobs in state %s", protoState)
+		}
+		filter.States = append(filter.States, state)
+	}
+
+	removed := s.manager.PruneJobs(filter)
+	slog.Info("pruned jobs", "user", id.Name, "count", len(removed))
+	return &pb.PruneJobsResponse{JobIds: removed}, nil
+}
+
+// GetCgroupGCReport returns the latest orphaned cgroup collection report, or
+// runs a collection first when requested. Orphaned cgroups belong to no job
+// owner, so only admins may inspect them.
+func (s *Server) GetCgroupGCReport(ctx context.Context, req *pb.CgroupGCReportRequest) (*pb.CgroupGCReportResponse, error) {
+	id, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !id.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "only admins may inspect orphaned cgroups")
+	}
+
+	var report jobmanager.CgroupGCReport
+	if req.Run {
+		report = s.manager.CollectOrphanedCgroups()
+		slog.Info("collected orphaned cgroups", "user", id.Name, "orphans", len(report.Orphans), "dry_run", report.DryRun)
+	} else {
+		var ok bool
+		if report, ok = s.manager.LastCgroupGCReport(); !ok {
+			return nil, status.Error(codes.NotFound, "no orphaned cgroup collection has run yet")
+		}
+	}
+
+	resp := &pb.CgroupGCReportResponse{
+		StartedAt:  toProtoTime(report.StartedAt),
+		FinishedAt: toProtoTime(report.FinishedAt),
+		DryRun:     report.DryRun,
+		Error:      report.Error,
+	}
+	for _, orphan := range report.Orphans {
+		pids := make([]int32, len(orphan.PIDs))
+		for i, pid := range orphan.PIDs {
+			pids[i] = int32(pid)
+		}
+		resp.Orphans = append(resp.Orphans, &pb.OrphanedCgroup{
+			Path:    orphan.Path,
+			JobId:   orphan.JobID,
+			Pids:    pids,
+			Removed: orphan.Removed,
+			Error:   orphan.Error,
+		})
+	}
+	return resp, nil
+}
@@ -1,84 +1,197 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	pb "github.com/arazmj/sentry-run/api/proto"
 	"github.com/arazmj/sentry-run/pkg/jobmanager"
+	"github.com/arazmj/sentry-run/pkg/limits"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type fakeJobManager struct {
 	startJob    *jobmanager.Job
 	startErr    error
 	startCall   startJobCall
+	getJobErr   error
 	stopErr     error
+	stopResult  jobmanager.StopResult
+	stopGrace   time.Duration
 	stoppedJob  string
-	status      map[string]bool
+	status      map[string]jobmanager.Status
 	statusErr   error
 	statusCalls []string
+	waitBlocks  bool
 	stdout      []byte
 	stderr      []byte
+	live        []byte
 	outputJob   string
 	jobs        []*jobmanager.Job
 	killErr     error
 	killedJob   string
+	removeErr   error
+	removedJob  string
+	pruneFilter jobmanager.PruneFilter
+	pruned      []string
+	gcReport    *jobmanager.CgroupGCReport
+	gcRuns      int
+	pids        uint64
+	updateJob   string
+	update      limits.Limits
+	updateErr   error
+	stats       jobmanager.Stats
+	statsErr    error
+	pressure    jobmanager.JobPressure
+	pressureErr error
 }
 
 type startJobCall struct {
+	owner       string
 	command     string
 	commandArgs []string
-	memoryLimit string
-	cpuLimit    string
-	mount       string
-	writeBps    string
-	readBps     string
+	limits      limits.Limits
+	opts        jobmanager.StartOptions
 }
 
-func (f *fakeJobManager) StartJob(command string, commandArgs []string, memoryLimit, cpuLimit, mount, writeBps, readBps string) (*jobmanager.Job, error) {
-	f.startCall = startJobCall{command, commandArgs, memoryLimit, cpuLimit, mount, writeBps, readBps}
+func (f *fakeJobManager) StartJob(owner, command string, commandArgs []string, jobLimits limits.Limits, opts jobmanager.StartOptions) (*jobmanager.Job, error) {
+	f.startCall = startJobCall{owner, command, commandArgs, jobLimits, opts}
 	return f.startJob, f.startErr
 }
-func (f *fakeJobManager) StopJob(jobID string) error { f.stoppedJob = jobID; return f.stopErr }
+func (f *fakeJobManager) StopJob(jobID string, gracePeriod time.Duration) (jobmanager.StopResult, error) {
+	f.stoppedJob = jobID
+	f.stopGrace = gracePeriod
+	return f.stopResult, f.stopErr
+}
 func (f *fakeJobManager) KillJob(jobID string) error { f.killedJob = jobID; return f.killErr }
-func (f *fakeJobManager) GetJobStatus(jobID string) (bool, error) {
+func (f *fakeJobManager) GetJob(jobID string) (*jobmanager.Job, error) {
+	if f.getJobErr != nil {
+		return nil, f.getJobErr
+	}
+	for _, job := range f.jobs {
+		if job.ID == jobID {
+			return job, nil
+		}
+	}
+	return &jobmanager.Job{ID: jobID}, nil
+}
+func (f *fakeJobManager) GetJobStatus(jobID string) (jobmanager.Status, error) {
 	f.statusCalls = append(f.statusCalls, jobID)
 	if f.statusErr != nil {
-		return false, f.statusErr
+		return jobmanager.Status{}, f.statusErr
 	}
 	return f.status[jobID], nil
 }
-func (f *fakeJobManager) GetJobOutput(jobID string) ([]byte, []byte, error) {
+func (f *fakeJobManager) GetJobPids(jobID string) (uint64, error) { return f.pids, nil }
+func (f *fakeJobManager) UpdateJobLimits(jobID string, update limits.Limits) (limits.Limits, error) {
+	f.updateJob, f.update = jobID, update
+	if f.updateErr != nil {
+		return limits.Limits{}, f.updateErr
+	}
+	return update, nil
+}
+func (f *fakeJobManager) GetJobStats(jobID string) (jobmanager.Stats, error) {
+	return f.stats, f.statsErr
+}
+func (f *fakeJobManager) GetJobPressure(jobID string) (jobmanager.JobPressure, error) {
+	return f.pressure, f.pressureErr
+}
+func (f *fakeJobManager) WaitJob(ctx context.Context, jobID string) (jobmanager.Status, error) {
+	if f.waitBlocks {
+		<-ctx.Done()
+		return jobmanager.Status{State: jobmanager.StateRunning}, ctx.Err()
+	}
+	return f.status[jobID], nil
+}
+func (f *fakeJobManager) OpenJobOutput(jobID string) (*jobmanager.OutputReader, *jobmanager.OutputReader, error) {
 	f.outputJob = jobID
-	return f.stdout, f.stderr, nil
+	return jobmanager.NewOutputReader(f.stdout), jobmanager.NewOutputReader(f.stderr), nil
+}
+func (f *fakeJobManager) ListJobs() []*jobmanager.Job  { return f.jobs }
+func (f *fakeJobManager) RemoveJob(jobID string) error { f.removedJob = jobID; return f.removeErr }
+func (f *fakeJobManager) PruneJobs(filter jobmanager.PruneFilter) []string {
+	f.pruneFilter = filter
+	return f.pruned
+}
+func (f *fakeJobManager) CollectOrphanedCgroups() jobmanager.CgroupGCReport {
+	f.gcRuns++
+	f.gcReport = &jobmanager.CgroupGCReport{Orphans: []jobmanager.OrphanedCgroup{{JobID: "stale", PIDs: []int{42}, Removed: true}}}
+	return *f.gcReport
+}
+func (f *fakeJobManager) LastCgroupGCReport() (jobmanager.CgroupGCReport, bool) {
+	if f.gcReport == nil {
+		return jobmanager.CgroupGCReport{}, false
+	}
+	return *f.gcReport, true
 }
-func (f *fakeJobManager) ListJobs() []*jobmanager.Job { return f.jobs }
 func (f *fakeJobManager) StreamOutput(ctx context.Context, jobID string, callback jobmanager.OutputCallback) error {
-	return nil
+	if f.live == nil {
+		return nil
+	}
+	return callback(f.live, false)
+}
+
+func withIdentity(name string, roles ...string) context.Context {
+	return context.WithValue(context.Background(), identityKey{}, Identity{Name: name, Roles: roles})
+}
+
+func adminContext() context.Context {
+	return withIdentity("root", adminRole)
 }
 
 func TestStartJobSuccess(t *testing.T) {
 	fake := &fakeJobManager{startJob: &jobmanager.Job{ID: "job-1"}}
-	req := &pb.StartJobRequest{Command: "echo", CommandArgs: []string{"hello"}, MemoryLimit: "128M", CpuLimit: "10000 100000", Mount: "/srv", WriteBps: "1M", ReadBps: "2M"}
-	resp, err := NewServer(fake).StartJob(context.Background(), req)
+	req := &pb.StartJobRequest{Command: "echo", CommandArgs: []string{"hello"}, MemoryLimit: "128M", CpuLimit: "10000 100000", Mount: "/srv", WriteBps: "1M", ReadBps: "2M", Network: "none", Image: "toolchain", KeepRootfs: true}
+	resp, err := NewServer(fake).StartJob(withIdentity("alice"), req)
 	if err != nil {
 		t.Fatalf("StartJob returned error: %v", err)
 	}
 	if resp.GetJobId() != "job-1" {
 		t.Fatalf("JobId = %q, want job-1", resp.GetJobId())
 	}
-	want := startJobCall{req.Command, req.CommandArgs, req.MemoryLimit, req.CpuLimit, req.Mount, req.WriteBps, req.ReadBps}
+	// Deprecated string limits are still accepted
+	wantLimits := limits.Limits{MemoryMax: 128 << 20, CPUQuota: 10000, CPUPeriod: 100000, IO: []limits.IOLimit{{ReadBps: 2 << 20, WriteBps: 1 << 20}}}
+	want := startJobCall{"alice", req.Command, req.CommandArgs, wantLimits, jobmanager.StartOptions{Mount: req.Mount, Network: jobmanager.NetworkNone, Image: "toolchain", KeepRootfs: true}}
 	if !reflect.DeepEqual(fake.startCall, want) {
 		t.Fatalf("StartJob call = %#v, want %#v", fake.startCall, want)
 	}
 }
 
+func TestStartJobRejectsInvalidLimits(t *testing.T) {
+	fake := &fakeJobManager{startJob: &jobmanager.Job{ID: "job-1"}}
+	req := &pb.StartJobRequest{Command: "echo", MemoryLimit: "lots"}
+	_, err := NewServer(fake).StartJob(adminContext(), req)
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "memory limit") {
+		t.Fatalf("error = %v, want InvalidArgument naming the memory limit", err)
+	}
+	if fake.startCall.command != "" {
+		t.Fatal("job was started despite invalid limits")
+	}
+}
+
+func TestStartJobRejectsInvalidNetwork(t *testing.T) {
+	fake := &fakeJobManager{startJob: &jobmanager.Job{ID: "job-1"}}
+	_, err := NewServer(fake).StartJob(adminContext(), &pb.StartJobRequest{Command: "echo", Network: "overlay"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+	if fake.startCall.command != "" {
+		t.Fatal("job was started despite an invalid network mode")
+	}
+}
+
 func TestStartJobError(t *testing.T) {
 	boom := errors.New("boom")
-	resp, err := NewServer(&fakeJobManager{startErr: boom}).StartJob(context.Background(), &pb.StartJobRequest{Command: "false"})
+	resp, err := NewServer(&fakeJobManager{startErr: boom}).StartJob(adminContext(), &pb.StartJobRequest{Command: "false"})
 	if !errors.Is(err, boom) {
 		t.Fatalf("error = %v, want %v", err, boom)
 	}
@@ -87,9 +200,29 @@ func TestStartJobError(t *testing.T) {
 	}
 }
 
+func TestStartJobInvalidOptions(t *testing.T) {
+	invalid := &jobmanager.InvalidOptionsError{Err: errors.New("unknown image \"missing\"")}
+	_, err := NewServer(&fakeJobManager{startErr: invalid}).StartJob(adminContext(), &pb.StartJobRequest{Command: "true", Image: "missing"})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "unknown image") {
+		t.Fatalf("error = %v, want InvalidArgument naming the image", err)
+	}
+}
+
+func TestStartJobInvalidIODevice(t *testing.T) {
+	manager := jobmanager.New()
+	req := &pb.StartJobRequest{Command: "true", Limits: &pb.ResourceLimits{Io: []*pb.DeviceIOLimit{{Device: "/nonexistent/disk", Rbps: 1 << 20}}}}
+	_, err := NewServer(manager).StartJob(adminContext(), req)
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "/nonexistent/disk") {
+		t.Fatalf("error = %v, want InvalidArgument naming the device", err)
+	}
+	if jobs := manager.ListJobs(); len(jobs) != 0 {
+		t.Fatalf("jobs = %v, want none", jobs)
+	}
+}
+
 func TestStopJobSuccess(t *testing.T) {
 	fake := &fakeJobManager{}
-	resp, err := NewServer(fake).StopJob(context.Background(), &pb.StopJobRequest{JobId: "job-1"})
+	resp, err := NewServer(fake).StopJob(adminContext(), &pb.StopJobRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("StopJob returned error: %v", err)
 	}
@@ -101,9 +234,27 @@ func TestStopJobSuccess(t *testing.T) {
 	}
 }
 
+func TestStopJobEscalated(t *testing.T) {
+	fake := &fakeJobManager{stopResult: jobmanager.StopResult{
+		Escalated: true,
+		Status:    jobmanager.Status{State: jobmanager.StateKilled, Signal: syscall.SIGKILL},
+	}}
+	req := &pb.StopJobRequest{JobId: "job-1", GracePeriod: durationpb.New(3 * time.Second)}
+	resp, err := NewServer(fake).StopJob(adminContext(), req)
+	if err != nil {
+		t.Fatalf("StopJob returned error: %v", err)
+	}
+	if !resp.GetSuccess() || !resp.GetEscalated() || resp.GetState() != pb.JobState_JOB_STATE_KILLED || !strings.Contains(resp.GetMessage(), "SIGKILL") {
+		t.Fatalf("response = %v, want escalated success", resp)
+	}
+	if fake.stopGrace != 3*time.Second {
+		t.Fatalf("grace period = %v, want 3s", fake.stopGrace)
+	}
+}
+
 func TestStopJobError(t *testing.T) {
 	boom := errors.New("stop failed")
-	resp, err := NewServer(&fakeJobManager{stopErr: boom}).StopJob(context.Background(), &pb.StopJobRequest{JobId: "job-1"})
+	resp, err := NewServer(&fakeJobManager{stopErr: boom}).StopJob(adminContext(), &pb.StopJobRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("handler error = %v", err)
 	}
@@ -113,17 +264,104 @@ func TestStopJobError(t *testing.T) {
 }
 
 func TestGetJobStatusSuccess(t *testing.T) {
-	resp, err := NewServer(&fakeJobManager{status: map[string]bool{"job-1": true}}).GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: "job-1"})
+	started := time.Now()
+	fake := &fakeJobManager{status: map[string]jobmanager.Status{"job-1": {State: jobmanager.StateRunning, ExitCode: -1, StartedAt: started}}}
+	resp, err := NewServer(fake).GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("GetJobStatus returned error: %v", err)
 	}
 	if !resp.GetIsRunning() {
 		t.Fatal("IsRunning = false, want true")
 	}
+	if resp.GetState() != pb.JobState_JOB_STATE_RUNNING || !resp.GetStartedAt().AsTime().Equal(started) || resp.GetFinishedAt() != nil {
+		t.Fatalf("response = %v, want running since %v", resp, started)
+	}
+}
+
+func TestGetJobStatusReportsPids(t *testing.T) {
+	fake := &fakeJobManager{
+		status: map[string]jobmanager.Status{"job-1": {State: jobmanager.StateRunning}},
+		jobs:   []*jobmanager.Job{{ID: "job-1", Limits: limits.Limits{PidsMax: 64}}},
+		pids:   3,
+	}
+	resp, err := NewServer(fake).GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("GetJobStatus returned error: %v", err)
+	}
+	if resp.GetPidsCurrent() != 3 || resp.GetPidsMax() != 64 {
+		t.Fatalf("pids = %d/%d, want 3/64", resp.GetPidsCurrent(), resp.GetPidsMax())
+	}
+}
+
+func TestGetJobStatusReportsPressure(t *testing.T) {
+	fake := &fakeJobManager{
+		status: map[string]jobmanager.Status{
+			"job-1": {State: jobmanager.StateRunning, Stalls: jobmanager.Stalls{Memory: 2}},
+			"job-2": {State: jobmanager.StateRunning},
+		},
+		pressure: jobmanager.JobPressure{Memory: jobmanager.Pressure{
+			Some: jobmanager.PressureLine{Avg10: 12.5, TotalUsec: 2500000},
+			Full: jobmanager.PressureLine{Avg60: 3},
+		}},
+	}
+	srv := NewServer(fake)
+	resp, err := srv.GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("GetJobStatus returned error: %v", err)
+	}
+	memory := resp.GetPressure().GetMemory()
+	if memory.GetSome().GetAvg10() != 12.5 || memory.GetSome().GetTotalUsec() != 2500000 || memory.GetFull().GetAvg60() != 3 {
+		t.Fatalf("memory pressure = %v", memory)
+	}
+	if resp.GetStalls().GetMemory() != 2 || resp.GetStalls().GetCpu() != 0 {
+		t.Fatalf("stalls = %v, want 2 memory stalls", resp.GetStalls())
+	}
+
+	// Jobs without a cgroup report no pressure
+	fake.pressureErr = errors.New("job job-2 was started without a cgroup")
+	resp, err = srv.GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-2"})
+	if err != nil || resp.GetPressure() != nil {
+		t.Fatalf("GetJobStatus() = (%v, %v), want no pressure", resp, err)
+	}
+}
+
+func TestGetJobStatusFinished(t *testing.T) {
+	fake := &fakeJobManager{status: map[string]jobmanager.Status{"job-1": {
+		State:      jobmanager.StateKilled,
+		ExitCode:   -1,
+		Signal:     syscall.SIGKILL,
+		StartedAt:  time.Now().Add(-time.Minute),
+		FinishedAt: time.Now(),
+	}}}
+	resp, err := NewServer(fake).GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("GetJobStatus returned error: %v", err)
+	}
+	if resp.GetIsRunning() || resp.GetState() != pb.JobState_JOB_STATE_KILLED || resp.GetExitCode() != -1 || resp.GetSignal() != int32(syscall.SIGKILL) || resp.GetFinishedAt() == nil {
+		t.Fatalf("response = %v, want killed by SIGKILL", resp)
+	}
+}
+
+func TestGetJobStatusOOMKilled(t *testing.T) {
+	fake := &fakeJobManager{status: map[string]jobmanager.Status{"job-1": {
+		State:     jobmanager.StateOOMKilled,
+		ExitCode:  -1,
+		Signal:    syscall.SIGKILL,
+		Error:     "1 process(es) killed by the OOM killer",
+		OOMEvents: 2,
+		OOMKills:  1,
+	}}}
+	resp, err := NewServer(fake).GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("GetJobStatus returned error: %v", err)
+	}
+	if resp.GetState() != pb.JobState_JOB_STATE_OOM_KILLED || resp.GetOomEvents() != 2 || resp.GetOomKills() != 1 || resp.GetError() == "" {
+		t.Fatalf("response = %v, want OOM-killed with its counters", resp)
+	}
 }
 
 func TestGetJobStatusError(t *testing.T) {
-	resp, err := NewServer(&fakeJobManager{statusErr: errors.New("missing")}).GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: "job-1"})
+	resp, err := NewServer(&fakeJobManager{statusErr: errors.New("missing")}).GetJobStatus(adminContext(), &pb.JobStatusRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("handler error = %v", err)
 	}
@@ -134,7 +372,7 @@ func TestGetJobStatusError(t *testing.T) {
 
 func TestGetJobLogsSuccess(t *testing.T) {
 	fake := &fakeJobManager{stdout: []byte("out"), stderr: []byte("err")}
-	resp, err := NewServer(fake).GetJobLogs(context.Background(), &pb.JobLogsRequest{JobId: "job-1"})
+	resp, err := NewServer(fake).GetJobLogs(adminContext(), &pb.JobLogsRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("GetJobLogs returned error: %v", err)
 	}
@@ -143,12 +381,80 @@ func TestGetJobLogsSuccess(t *testing.T) {
 		t.Fatalf("logs = %q, want %q", string(resp.GetLogs()), want)
 	}
 	if fake.outputJob != "job-1" {
-		t.Fatalf("GetJobOutput called with %q", fake.outputJob)
+		t.Fatalf("OpenJobOutput called with %q", fake.outputJob)
+	}
+}
+
+func TestGetJobLogsTruncatesLargeOutput(t *testing.T) {
+	stdout := append(bytes.Repeat([]byte("a"), 10), bytes.Repeat([]byte("b"), logsTailLimit)...)
+	fake := &fakeJobManager{stdout: stdout, stderr: []byte("err")}
+	resp, err := NewServer(fake).GetJobLogs(adminContext(), &pb.JobLogsRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("GetJobLogs returned error: %v", err)
+	}
+	header := fmt.Sprintf("=== STDOUT (last %d of %d bytes, stream the logs for all) ===\n", logsTailLimit, len(stdout))
+	want := header + string(stdout[10:]) + "\n=== STDERR ===\nerr"
+	if string(resp.GetLogs()) != want {
+		t.Fatalf("logs has %d bytes, want the %d byte tail with a marker", len(resp.GetLogs()), len(want))
+	}
+}
+
+type fakeLogsStream struct {
+	fakeServerStream
+	outputs []*pb.JobOutput
+}
+
+func (f *fakeLogsStream) Send(output *pb.JobOutput) error {
+	f.outputs = append(f.outputs, output)
+	return nil
+}
+
+func TestStreamJobLogsSendsHistoryInChunks(t *testing.T) {
+	stdout := bytes.Repeat([]byte("x"), 2*logChunkSize+1)
+	fake := &fakeJobManager{stdout: stdout, stderr: []byte("err")}
+	stream := &fakeLogsStream{fakeServerStream: fakeServerStream{ctx: adminContext()}}
+	if err := NewServer(fake).StreamJobLogs(&pb.JobLogsRequest{JobId: "job-1"}, stream); err != nil {
+		t.Fatalf("StreamJobLogs returned error: %v", err)
+	}
+
+	var got []byte
+	for i, output := range stream.outputs {
+		if len(output.GetData()) > logChunkSize {
+			t.Fatalf("message %d has %d bytes, want at most %d", i, len(output.GetData()), logChunkSize)
+		}
+		if !output.GetIsStderr() {
+			got = append(got, output.GetData()...)
+		}
+	}
+	if len(stream.outputs) != 4 || !bytes.Equal(got, stdout) || string(stream.outputs[3].GetData()) != "err" {
+		t.Fatalf("sent %d messages with %d stdout bytes, want 3 stdout chunks of %d bytes then stderr", len(stream.outputs), len(got), len(stdout))
+	}
+}
+
+func TestStreamJobLogsHistoryOnly(t *testing.T) {
+	for _, historyOnly := range []bool{false, true} {
+		fake := &fakeJobManager{stdout: []byte("old"), live: []byte("new")}
+		stream := &fakeLogsStream{fakeServerStream: fakeServerStream{ctx: adminContext()}}
+		if err := NewServer(fake).StreamJobLogs(&pb.JobLogsRequest{JobId: "job-1", HistoryOnly: historyOnly}, stream); err != nil {
+			t.Fatalf("StreamJobLogs(history_only=%v) returned error: %v", historyOnly, err)
+		}
+
+		var got []byte
+		for _, output := range stream.outputs {
+			got = append(got, output.GetData()...)
+		}
+		want := "oldnew"
+		if historyOnly {
+			want = "old"
+		}
+		if string(got) != want {
+			t.Errorf("StreamJobLogs(history_only=%v) sent %q, want %q", historyOnly, got, want)
+		}
 	}
 }
 
 func TestListJobsEmpty(t *testing.T) {
-	resp, err := NewServer(&fakeJobManager{}).ListJobs(context.Background(), &pb.ListJobsRequest{})
+	resp, err := NewServer(&fakeJobManager{}).ListJobs(adminContext(), &pb.ListJobsRequest{})
 	if err != nil {
 		t.Fatalf("ListJobs returned error: %v", err)
 	}
@@ -160,22 +466,25 @@ func TestListJobsEmpty(t *testing.T) {
 func TestListJobsMultiple(t *testing.T) {
 	fake := &fakeJobManager{
 		jobs: []*jobmanager.Job{
-			{ID: "job-1", Command: "echo", MemoryLimit: "128M", CpuLimit: "10000 100000", Mount: "/srv", ReadBps: "1M", WriteBps: "2M"},
+			{ID: "job-1", Owner: "alice", Command: "echo", Limits: limits.Limits{MemoryMax: 128 << 20, CPUQuota: 10000, CPUPeriod: 100000, IO: []limits.IOLimit{{ReadBps: 1 << 20, WriteBps: 2 << 20}}}, Mount: "/srv", DeviceId: "259:0", Isolated: true, Network: jobmanager.NetworkBridged, Address: "10.88.0.2/16", Image: "toolchain", LayerDir: "/var/lib/sentry-run/layers/job-1", KeepRootfs: true},
 			{ID: "job-2", Command: "sleep"},
 		},
-		status: map[string]bool{"job-1": true, "job-2": false},
+		status: map[string]jobmanager.Status{"job-1": {State: jobmanager.StateRunning}, "job-2": {State: jobmanager.StateExited}},
 	}
-	resp, err := NewServer(fake).ListJobs(context.Background(), &pb.ListJobsRequest{})
+	resp, err := NewServer(fake).ListJobs(adminContext(), &pb.ListJobsRequest{})
 	if err != nil {
 		t.Fatalf("ListJobs returned error: %v", err)
 	}
 	if len(resp.GetJobs()) != 2 {
 		t.Fatalf("got %d jobs, want 2", len(resp.GetJobs()))
 	}
-	if got := resp.GetJobs()[0]; got.GetJobId() != "job-1" || got.GetCommand() != "echo" || !got.GetIsRunning() || got.GetMemoryLimit() != "128M" || got.GetCpuLimit() != "10000 100000" || got.GetMount() != "/srv" || got.GetReadBps() != "1M" || got.GetWriteBps() != "2M" {
+	if got := resp.GetJobs()[0]; got.GetJobId() != "job-1" || got.GetOwner() != "alice" || got.GetCommand() != "echo" || !got.GetIsRunning() || got.GetState() != pb.JobState_JOB_STATE_RUNNING || got.GetMemoryLimit() != "134217728" || got.GetCpuLimit() != "10000 100000" || got.GetMount() != "/srv" || got.GetReadBps() != "1048576" || got.GetWriteBps() != "2097152" || got.GetDeviceId() != "259:0" || !got.GetIsolated() || got.GetNetwork() != "bridged" || got.GetIpAddress() != "10.88.0.2/16" || got.GetImage() != "toolchain" || got.GetUpperDir() != "/var/lib/sentry-run/layers/job-1/upper" || !got.GetKeepRootfs() {
 		t.Fatalf("first job = %#v", got)
 	}
-	if got := resp.GetJobs()[1]; got.GetJobId() != "job-2" || got.GetCommand() != "sleep" || got.GetIsRunning() {
+	if got := resp.GetJobs()[0].GetLimits(); got.GetMemoryMaxBytes() != 128<<20 || got.GetCpuMillicores() != 100 || len(got.GetIo()) != 1 || got.GetIo()[0].GetWbps() != 2<<20 {
+		t.Fatalf("first job = %#v", got)
+	}
+	if got := resp.GetJobs()[1]; got.GetJobId() != "job-2" || got.GetCommand() != "sleep" || got.GetIsRunning() || got.GetState() != pb.JobState_JOB_STATE_EXITED {
 		t.Fatalf("second job = %#v", got)
 	}
 	if !reflect.DeepEqual(fake.statusCalls, []string{"job-1", "job-2"}) {
@@ -183,9 +492,75 @@ func TestListJobsMultiple(t *testing.T) {
 	}
 }
 
+func TestUpdateJobLimits(t *testing.T) {
+	fake := &fakeJobManager{}
+	req := &pb.UpdateJobLimitsRequest{JobId: "job-1", Limits: &pb.ResourceLimits{MemoryMaxBytes: 2 << 30, CpuMillicores: 500}}
+	resp, err := NewServer(fake).UpdateJobLimits(adminContext(), req)
+	if err != nil {
+		t.Fatalf("UpdateJobLimits returned error: %v", err)
+	}
+	want := limits.Limits{MemoryMax: 2 << 30, CPUQuota: 50000, CPUPeriod: 100000}
+	if !resp.GetSuccess() || fake.updateJob != "job-1" || !reflect.DeepEqual(fake.update, want) {
+		t.Fatalf("response = %v, update = %s %+v, want %+v", resp, fake.updateJob, fake.update, want)
+	}
+	if resp.GetLimits().GetMemoryMaxBytes() != 2<<30 {
+		t.Fatalf("limits = %v, want the updated limits", resp.GetLimits())
+	}
+}
+
+func TestUpdateJobLimitsErrors(t *testing.T) {
+	srv := NewServer(&fakeJobManager{updateErr: errors.New("job job-1 is not running")})
+	for name, limits := range map[string]*pb.ResourceLimits{
+		"missing": nil,
+		"invalid": {CpuWeight: 20000},
+	} {
+		_, err := srv.UpdateJobLimits(adminContext(), &pb.UpdateJobLimitsRequest{JobId: "job-1", Limits: limits})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: error = %v, want InvalidArgument", name, err)
+		}
+	}
+
+	resp, err := srv.UpdateJobLimits(adminContext(), &pb.UpdateJobLimitsRequest{JobId: "job-1", Limits: &pb.ResourceLimits{PidsMax: 10}})
+	if err != nil || resp.GetSuccess() || resp.GetMessage() != "job job-1 is not running" {
+		t.Fatalf("UpdateJobLimits() = (%v, %v), want failure", resp, err)
+	}
+}
+
+func TestGetJobStats(t *testing.T) {
+	fake := &fakeJobManager{stats: jobmanager.Stats{
+		CollectedAt: time.Now(),
+		CPU:         jobmanager.CPUStats{UsageUsec: 1500, NrThrottled: 2},
+		Memory:      jobmanager.MemoryStats{Current: 4096, Stat: map[string]uint64{"anon": 1024}, Events: jobmanager.MemoryEvents{OOMKill: 1}},
+		IO:          []jobmanager.IOStats{{Device: "259:0", WriteBytes: 200}},
+		PidsCurrent: 3,
+		Pressure:    jobmanager.JobPressure{IO: jobmanager.Pressure{Full: jobmanager.PressureLine{Avg300: 1.5}}},
+	}}
+	resp, err := NewServer(fake).GetJobStats(adminContext(), &pb.JobStatsRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("GetJobStats returned error: %v", err)
+	}
+	if resp.GetCpu().GetUsageUsec() != 1500 || resp.GetCpu().GetNrThrottled() != 2 || resp.GetMemory().GetCurrentBytes() != 4096 ||
+		resp.GetMemory().GetStat()["anon"] != 1024 || resp.GetMemory().GetEventsOomKill() != 1 || resp.GetPidsCurrent() != 3 || resp.GetCollectedAt() == nil {
+		t.Fatalf("response = %v", resp)
+	}
+	if len(resp.GetIo()) != 1 || resp.GetIo()[0].GetDevice() != "259:0" || resp.GetIo()[0].GetWriteBytes() != 200 {
+		t.Fatalf("io = %v", resp.GetIo())
+	}
+	if resp.GetPressure().GetIo().GetFull().GetAvg300() != 1.5 {
+		t.Fatalf("pressure = %v", resp.GetPressure())
+	}
+}
+
+func TestGetJobStatsError(t *testing.T) {
+	_, err := NewServer(&fakeJobManager{statsErr: errors.New("job job-1 is not running")}).GetJobStats(adminContext(), &pb.JobStatsRequest{JobId: "job-1"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("error = %v, want FailedPrecondition", err)
+	}
+}
+
 func TestKillJobSuccess(t *testing.T) {
 	fake := &fakeJobManager{}
-	resp, err := NewServer(fake).KillJob(context.Background(), &pb.KillJobRequest{JobId: "job-1"})
+	resp, err := NewServer(fake).KillJob(adminContext(), &pb.KillJobRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("KillJob returned error: %v", err)
 	}
@@ -199,7 +574,7 @@ func TestKillJobSuccess(t *testing.T) {
 
 func TestKillJobError(t *testing.T) {
 	boom := errors.New("kill failed")
-	resp, err := NewServer(&fakeJobManager{killErr: boom}).KillJob(context.Background(), &pb.KillJobRequest{JobId: "job-1"})
+	resp, err := NewServer(&fakeJobManager{killErr: boom}).KillJob(adminContext(), &pb.KillJobRequest{JobId: "job-1"})
 	if err != nil {
 		t.Fatalf("handler error = %v", err)
 	}
@@ -207,3 +582,170 @@ func TestKillJobError(t *testing.T) {
 		t.Fatalf("response = %#v, want failure", resp)
 	}
 }
+
+func TestStartJobWithoutIdentity(t *testing.T) {
+	fake := &fakeJobManager{startJob: &jobmanager.Job{ID: "job-1"}}
+	_, err := NewServer(fake).StartJob(context.Background(), &pb.StartJobRequest{Command: "echo"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("error = %v, want Unauthenticated", err)
+	}
+	if fake.startCall.command != "" {
+		t.Fatal("StartJob was called without a caller identity")
+	}
+}
+
+func TestListJobsScopedToOwner(t *testing.T) {
+	fake := &fakeJobManager{
+		jobs: []*jobmanager.Job{
+			{ID: "job-1", Owner: "alice"},
+			{ID: "job-2", Owner: "bob"},
+		},
+	}
+	resp, err := NewServer(fake).ListJobs(withIdentity("bob"), &pb.ListJobsRequest{})
+	if err != nil {
+		t.Fatalf("ListJobs returned error: %v", err)
+	}
+	if len(resp.GetJobs()) != 1 || resp.GetJobs()[0].GetJobId() != "job-2" {
+		t.Fatalf("jobs = %v, want only job-2", resp.GetJobs())
+	}
+}
+
+func TestJobAccessDeniedForOtherOwner(t *testing.T) {
+	fake := &fakeJobManager{jobs: []*jobmanager.Job{{ID: "job-1", Owner: "alice"}}}
+	srv := NewServer(fake)
+	ctx := withIdentity("bob")
+
+	if _, err := srv.StopJob(ctx, &pb.StopJobRequest{JobId: "job-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("StopJob error = %v, want PermissionDenied", err)
+	}
+	if _, err := srv.KillJob(ctx, &pb.KillJobRequest{JobId: "job-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("KillJob error = %v, want PermissionDenied", err)
+	}
+	if _, err := srv.GetJobLogs(ctx, &pb.JobLogsRequest{JobId: "job-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("GetJobLogs error = %v, want PermissionDenied", err)
+	}
+	if fake.stoppedJob != "" || fake.killedJob != "" || fake.outputJob != "" {
+		t.Fatal("manager was called for a job the caller does not own")
+	}
+}
+
+func TestJobAccessAllowedForOwner(t *testing.T) {
+	fake := &fakeJobManager{jobs: []*jobmanager.Job{{ID: "job-1", Owner: "alice"}}}
+	resp, err := NewServer(fake).KillJob(withIdentity("alice"), &pb.KillJobRequest{JobId: "job-1"})
+	if err != nil || !resp.GetSuccess() {
+		t.Fatalf("KillJob = (%v, %v), want success", resp, err)
+	}
+}
+
+func TestJobAccessUnknownJob(t *testing.T) {
+	fake := &fakeJobManager{getJobErr: errors.New("job missing not found")}
+	_, err := NewServer(fake).StopJob(adminContext(), &pb.StopJobRequest{JobId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("StopJob error = %v, want NotFound", err)
+	}
+}
+
+func TestWaitJobReturnsFinalStatus(t *testing.T) {
+	fake := &fakeJobManager{status: map[string]jobmanager.Status{"job-1": {State: jobmanager.StateExited, ExitCode: 2}}}
+	resp, err := NewServer(fake).WaitJob(adminContext(), &pb.WaitJobRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("WaitJob returned error: %v", err)
+	}
+	if resp.GetState() != pb.JobState_JOB_STATE_EXITED || resp.GetExitCode() != 2 {
+		t.Fatalf("response = %v, want exited with code 2", resp)
+	}
+}
+
+func TestWaitJobTimeout(t *testing.T) {
+	fake := &fakeJobManager{waitBlocks: true}
+	req := &pb.WaitJobRequest{JobId: "job-1", Timeout: durationpb.New(10 * time.Millisecond)}
+	_, err := NewServer(fake).WaitJob(adminContext(), req)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("error = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestRemoveJobSuccess(t *testing.T) {
+	fake := &fakeJobManager{}
+	resp, err := NewServer(fake).RemoveJob(adminContext(), &pb.RemoveJobRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("RemoveJob returned error: %v", err)
+	}
+	if !resp.GetSuccess() || fake.removedJob != "job-1" {
+		t.Fatalf("response = %v, removed %q, want success for job-1", resp, fake.removedJob)
+	}
+}
+
+func TestRemoveJobError(t *testing.T) {
+	boom := errors.New("job job-1 is still running, stop it first")
+	resp, err := NewServer(&fakeJobManager{removeErr: boom}).RemoveJob(adminContext(), &pb.RemoveJobRequest{JobId: "job-1"})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if resp.GetSuccess() || resp.GetMessage() != boom.Error() {
+		t.Fatalf("response = %v, want failure", resp)
+	}
+}
+
+func TestPruneJobsScopedToOwner(t *testing.T) {
+	fake := &fakeJobManager{pruned: []string{"job-1"}}
+	req := &pb.PruneJobsRequest{
+		OlderThan: durationpb.New(time.Hour),
+		States:    []pb.JobState{pb.JobState_JOB_STATE_EXITED, pb.JobState_JOB_STATE_OOM_KILLED},
+	}
+	resp, err := NewServer(fake).PruneJobs(withIdentity("bob"), req)
+	if err != nil {
+		t.Fatalf("PruneJobs returned error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.GetJobIds(), []string{"job-1"}) {
+		t.Fatalf("job ids = %v, want [job-1]", resp.GetJobIds())
+	}
+	want := jobmanager.PruneFilter{
+		Owner:     "bob",
+		OlderThan: time.Hour,
+		States:    []jobmanager.State{jobmanager.StateExited, jobmanager.StateOOMKilled},
+	}
+	if !reflect.DeepEqual(fake.pruneFilter, want) {
+		t.Fatalf("filter = %+v, want %+v", fake.pruneFilter, want)
+	}
+}
+
+func TestPruneJobsRejectsRunningState(t *testing.T) {
+	req := &pb.PruneJobsRequest{States: []pb.JobState{pb.JobState_JOB_STATE_RUNNING}}
+	_, err := NewServer(&fakeJobManager{}).PruneJobs(adminContext(), req)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGetCgroupGCReport(t *testing.T) {
+	fake := &fakeJobManager{}
+	srv := NewServer(fake)
+
+	if _, err := srv.GetCgroupGCReport(adminContext(), &pb.CgroupGCReportRequest{}); status.Code(err) != codes.NotFound {
+		t.Fatalf("report before any run error = %v, want NotFound", err)
+	}
+
+	resp, err := srv.GetCgroupGCReport(adminContext(), &pb.CgroupGCReportRequest{Run: true})
+	if err != nil {
+		t.Fatalf("GetCgroupGCReport returned error: %v", err)
+	}
+	if fake.gcRuns != 1 || len(resp.GetOrphans()) != 1 {
+		t.Fatalf("runs = %d, orphans = %v, want one run and one orphan", fake.gcRuns, resp.GetOrphans())
+	}
+	orphan := resp.GetOrphans()[0]
+	if orphan.GetJobId() != "stale" || !orphan.GetRemoved() || !reflect.DeepEqual(orphan.GetPids(), []int32{42}) {
+		t.Fatalf("orphan = %v", orphan)
+	}
+
+	if _, err := srv.GetCgroupGCReport(adminContext(), &pb.CgroupGCReportRequest{}); err != nil || fake.gcRuns != 1 {
+		t.Fatalf("latest report error = %v, runs = %d", err, fake.gcRuns)
+	}
+}
+
+func TestGetCgroupGCReportRequiresAdmin(t *testing.T) {
+	_, err := NewServer(&fakeJobManager{}).GetCgroupGCReport(withIdentity("bob"), &pb.CgroupGCReportRequest{Run: true})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("error = %v, want PermissionDenied", err)
+	}
+}